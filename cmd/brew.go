@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/yyYank/goodbye/internal/asdf"
+	"github.com/yyYank/goodbye/internal/brew"
 	"github.com/yyYank/goodbye/internal/config"
 	"github.com/yyYank/goodbye/internal/mise"
+	"github.com/yyYank/goodbye/internal/uv"
 )
 
 var brewCmd = &cobra.Command{
@@ -14,32 +18,153 @@ var brewCmd = &cobra.Command{
 	Long: `Migrate tools from Homebrew to other package managers.
 
 Use --mise to migrate Homebrew-managed tools that can be replaced by mise.
+Use --asdf to migrate them to asdf, pinning the brew-installed version
+in ~/.tool-versions.
+Use --uv to migrate Python CLI tools to uv tool.
 By default the command runs in dry-run mode — only candidates are shown.`,
 	Example: `  # Preview migration candidates (dry-run)
   goodbye brew --mise
 
   # Actually perform migration
-  goodbye brew --mise --apply`,
+  goodbye brew --mise --apply
+
+  # Keep the exact brew-installed versions
+  goodbye brew --mise --version-strategy keep-exact
+
+  # Choose candidates one by one
+  goodbye brew --mise --apply --interactive --exclude python
+
+  # Undo recorded mise migrations (dry-run)
+  goodbye brew --mise --rollback
+
+  # Preview migration to asdf (dry-run)
+  goodbye brew --asdf
+
+  # Actually perform migration to asdf
+  goodbye brew --asdf --apply
+
+  # Preview migration of Python CLI tools to uv (dry-run)
+  goodbye brew --uv`,
 	RunE: runBrew,
 }
 
+var brewAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Find requested formulas that are only used as dependencies",
+	Long: `Analyze the installed Homebrew dependency graph.
+
+Each installed formula is classified as:
+  leaf        nothing installed depends on it
+  dependency  other installed formulas depend on it
+  orphan      installed as a dependency that nothing needs anymore
+
+Requested formulas that only act as dependencies are flagged, and a
+trimmed formula list is suggested. With --apply, they are removed from
+the formula list in --dir.`,
+	Example: `  # Show the analysis (dry-run)
+  goodbye brew analyze
+
+  # Machine-readable output
+  goodbye brew analyze --json
+
+  # Remove flagged formulas from an export
+  goodbye brew analyze --dir ~/goodbye-export --apply`,
+	RunE: runBrewAnalyze,
+}
+
+var brewCompatCmd = &cobra.Command{
+	Use:   "compat",
+	Short: "Check whether an export can be installed on another architecture or OS",
+	Long: `Check an export against a target architecture and OS before running
+'goodbye import brew' there.
+
+Bottle availability for each exported formula and cask is read from
+'brew info --json=v2', and each package is reported as:
+  bottle       a bottle is available for the target
+  source       no bottle for the target; brew builds it from source
+  rosetta      Intel-only cask that needs Rosetta 2 on Apple Silicon
+  macos-only   requires macOS (every cask when the target is Linux)
+  unsupported  cannot be installed on the target
+  unknown      not found by brew info
+
+The target defaults to this machine.`,
+	Example: `  # Intel Mac -> Apple Silicon
+  goodbye brew compat --dir ~/goodbye-export --target darwin/arm64
+
+  # macOS -> Linuxbrew
+  goodbye brew compat --dir ~/goodbye-export --target linux/x86_64`,
+	RunE: runBrewCompat,
+}
+
+var (
+	brewCompatDir    string
+	brewCompatTarget string
+	brewCompatFormat string
+	brewCompatJSON   bool
+)
+
+var (
+	brewAnalyzeDir  string
+	brewAnalyzeJSON bool
+)
+
 var (
 	brewMise    bool
+	brewAsdf    bool
+	brewUv      bool
 	brewApply   bool
 	brewVerbose bool
+
+	brewVersionStrategy string
+	brewOnly            []string
+	brewExclude         []string
+	brewInteractive     bool
+	brewRollback        bool
 )
 
 func init() {
 	rootCmd.AddCommand(brewCmd)
+	brewCmd.AddCommand(brewAnalyzeCmd)
+	brewCmd.AddCommand(brewCompatCmd)
 
 	brewCmd.Flags().BoolVar(&brewMise, "mise", false, "Migrate tools from Homebrew to mise")
+	brewCmd.Flags().BoolVar(&brewAsdf, "asdf", false, "Migrate tools from Homebrew to asdf")
+	brewCmd.Flags().BoolVar(&brewUv, "uv", false, "Migrate Python CLI tools from Homebrew to uv")
 	brewCmd.Flags().BoolVar(&brewApply, "apply", false, "Actually perform the migration (default is dry-run)")
 	brewCmd.Flags().BoolVarP(&brewVerbose, "verbose", "v", false, "Verbose output")
+	brewCmd.Flags().StringSliceVar(&brewOnly, "only", nil, "Migrate only these tools with --mise (comma-separated)")
+	brewCmd.Flags().StringSliceVar(&brewExclude, "exclude", nil, "Do not migrate these tools with --mise (comma-separated)")
+	brewCmd.Flags().BoolVar(&brewInteractive, "interactive", false, "Ask for each --mise candidate (yes/no/skip forever)")
+	brewCmd.Flags().BoolVar(&brewRollback, "rollback", false, "Undo migrations recorded in the --mise migration journal")
+	brewCmd.Flags().StringVar(&brewVersionStrategy, "version-strategy", "", "Version to install with --mise: latest, keep-major or keep-exact (default: keep-major)")
+
+	brewAnalyzeCmd.Flags().StringVar(&brewAnalyzeDir, "dir", ".", "Export directory containing the formula list to rewrite")
+	brewAnalyzeCmd.Flags().BoolVar(&brewApply, "apply", false, "Rewrite the formula list (default is dry-run)")
+	brewAnalyzeCmd.Flags().BoolVarP(&brewVerbose, "verbose", "v", false, "Also list formulas installed only as dependencies")
+	brewAnalyzeCmd.Flags().BoolVar(&brewAnalyzeJSON, "json", false, "Output the analysis as JSON")
+
+	brewCompatCmd.Flags().StringVar(&brewCompatDir, "dir", ".", "Directory containing the export")
+	brewCompatCmd.Flags().StringVar(&brewCompatTarget, "target", "", "Target as <os>/<arch> (darwin/arm64, darwin/x86_64, linux/x86_64, linux/arm64)")
+	brewCompatCmd.Flags().StringVar(&brewCompatFormat, "format", "", "Input format (txt or brewfile, default: auto-detect)")
+	brewCompatCmd.Flags().BoolVarP(&brewVerbose, "verbose", "v", false, "Also list packages that have bottles")
+	brewCompatCmd.Flags().BoolVar(&brewCompatJSON, "json", false, "Output the report as JSON")
 }
 
 func runBrew(cmd *cobra.Command, args []string) error {
-	if !brewMise {
-		return fmt.Errorf("please specify a migration target (e.g., --mise)")
+	targets := 0
+	for _, selected := range []bool{brewMise, brewAsdf, brewUv} {
+		if selected {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return fmt.Errorf("please specify only one migration target")
+	}
+	if targets == 0 {
+		return fmt.Errorf("please specify a migration target (e.g., --mise, --asdf or --uv)")
+	}
+	if brewRollback && !brewMise {
+		return fmt.Errorf("--rollback is only supported with --mise")
 	}
 
 	cfg, err := config.Load()
@@ -47,10 +172,72 @@ func runBrew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if brewAsdf {
+		opts := asdf.MigrateOptions{
+			DryRun:  !brewApply,
+			Verbose: brewVerbose,
+		}
+		return asdf.Migrate(cfg, opts)
+	}
+
+	if brewUv {
+		opts := uv.MigrateOptions{
+			DryRun:  !brewApply,
+			Verbose: brewVerbose,
+		}
+		return uv.Migrate(cfg, opts)
+	}
+
+	if brewRollback {
+		opts := mise.RollbackOptions{
+			DryRun:  !brewApply,
+			Verbose: brewVerbose,
+			Only:    brewOnly,
+		}
+		return mise.Rollback(cfg, opts)
+	}
+
 	opts := mise.MigrateOptions{
+		DryRun:          !brewApply,
+		Verbose:         brewVerbose,
+		VersionStrategy: brewVersionStrategy,
+		Only:            brewOnly,
+		Exclude:         brewExclude,
+		Interactive:     brewInteractive,
+	}
+
+	return mise.Migrate(cfg, opts)
+}
+
+func runBrewAnalyze(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	opts := brew.AnalyzeOptions{
+		Dir:     brewAnalyzeDir,
 		DryRun:  !brewApply,
 		Verbose: brewVerbose,
+		JSON:    brewAnalyzeJSON,
 	}
 
-	return mise.Migrate(cfg, opts)
+	return brew.Analyze(cfg, opts, os.Stdout)
+}
+
+func runBrewCompat(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	opts := brew.CompatOptions{
+		Dir:     brewCompatDir,
+		Target:  brewCompatTarget,
+		Format:  brewCompatFormat,
+		Verbose: brewVerbose,
+		JSON:    brewCompatJSON,
+	}
+
+	return brew.Compat(cfg, opts, os.Stdout)
 }
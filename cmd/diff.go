@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yyYank/goodbye/internal/brew"
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare exported configurations with the current system",
+	Long:  `Compare exported package manager configurations with the current system.`,
+}
+
+var diffBrewCmd = &cobra.Command{
+	Use:   "brew",
+	Short: "Compare a Homebrew export with installed packages",
+	Long: `Compare the files created by 'goodbye export brew' with the packages
+installed on the current system.
+
+Installed packages are listed with the same commands as export
+(configurable in ~/.goodbye.toml). For formulas, casks and taps the
+command prints:
+  + installed but not exported
+  - exported but not installed
+
+The command exits with a non-zero status when drift exists, so it can
+be used in scheduled checks.`,
+	Example: `  # Show drift against an export directory
+  goodbye diff brew --dir ~/goodbye-export
+
+  # Machine-readable output
+  goodbye diff brew --dir ~/goodbye-export --json`,
+	RunE: runDiffBrew,
+}
+
+var (
+	diffDir     string
+	diffVerbose bool
+	diffJSON    bool
+	diffFormat  string
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.AddCommand(diffBrewCmd)
+
+	diffBrewCmd.Flags().StringVar(&diffDir, "dir", ".", "Directory containing exported files")
+	diffBrewCmd.Flags().BoolVarP(&diffVerbose, "verbose", "v", false, "Also list packages present in both")
+	diffBrewCmd.Flags().BoolVar(&diffJSON, "json", false, "Output the result as JSON")
+	diffBrewCmd.Flags().StringVar(&diffFormat, "format", "", "Input format (txt or brewfile, default: auto-detect)")
+}
+
+func runDiffBrew(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	opts := brew.DiffOptions{
+		Dir:     diffDir,
+		Verbose: diffVerbose,
+		JSON:    diffJSON,
+		Format:  diffFormat,
+	}
+
+	err = brew.Diff(cfg, opts, os.Stdout)
+	if errors.Is(err, brew.ErrDrift) {
+		// Drift is a result, not a usage error
+		cmd.SilenceUsage = true
+	}
+	return err
+}
@@ -25,14 +25,22 @@ By default, exports:
   - formula: brew list --installed-on-request
   - cask: brew list --cask
   - tap: brew tap
+  - mas: mas list (Mac App Store apps, skipped if mas is not installed)
 
-These commands can be customized in ~/.goodbye.toml`,
+These commands can be customized in ~/.goodbye.toml
+
+With --format brewfile, a single Brewfile compatible with 'brew bundle'
+is written instead, including tap/brew/cask/mas directives and the
+link and restart_service options.`,
 	Example: `  # Dry-run (default) - preview what will be exported
   goodbye export brew
 
   # Export to specific directory
   goodbye export brew --dir ~/goodbye-export
 
+  # Export as a Brewfile
+  goodbye export brew --dir ~/goodbye-export --format brewfile --apply
+
   # Actually export
   goodbye export brew --dir ~/goodbye-export --apply`,
 	RunE: runExportBrew,
@@ -43,9 +51,15 @@ var exportMiseCmd = &cobra.Command{
 	Short: "Export mise configuration",
 	Long: `Export the current mise environment to a configuration file.
 
-Exports all installed mise tools to either:
+Exports the mise tools from 'mise ls --json' to either:
   - .mise.toml (default)
-  - .tool-versions`,
+  - .tool-versions
+
+By default the requested version specs (e.g., node = "20") of the versions
+set by a config are written, leaving out stale installs. With --exact,
+the versions they resolve to (e.g., node = "20.10.0") are written instead.
+With --active-only, only the versions active in the current directory are
+exported.`,
 	Example: `  # Dry-run (default) - preview what will be exported
   goodbye export mise
 
@@ -55,6 +69,9 @@ Exports all installed mise tools to either:
   # Export as .tool-versions format
   goodbye export mise --format tool-versions --apply
 
+  # Pin the resolved versions of the active tools
+  goodbye export mise --exact --active-only
+
   # Actually export
   goodbye export mise --dir ~/goodbye-export --apply`,
 	RunE: runExportMise,
@@ -65,6 +82,10 @@ var (
 	exportApply      bool
 	exportVerbose    bool
 	exportMiseFormat string
+	exportBrewFormat string
+	exportActiveOnly bool
+	exportExact      bool
+	exportRequested  bool
 )
 
 func init() {
@@ -75,11 +96,16 @@ func init() {
 	exportBrewCmd.Flags().StringVar(&exportDir, "dir", ".", "Output directory for exported files")
 	exportBrewCmd.Flags().BoolVar(&exportApply, "apply", false, "Actually perform the export (default is dry-run)")
 	exportBrewCmd.Flags().BoolVarP(&exportVerbose, "verbose", "v", false, "Verbose output")
+	exportBrewCmd.Flags().StringVar(&exportBrewFormat, "format", "txt", "Output format (txt or brewfile)")
 
 	exportMiseCmd.Flags().StringVar(&exportDir, "dir", ".", "Output directory for exported files")
 	exportMiseCmd.Flags().BoolVar(&exportApply, "apply", false, "Actually perform the export (default is dry-run)")
 	exportMiseCmd.Flags().BoolVarP(&exportVerbose, "verbose", "v", false, "Verbose output")
 	exportMiseCmd.Flags().StringVar(&exportMiseFormat, "format", "toml", "Output format (toml or tool-versions)")
+	exportMiseCmd.Flags().BoolVar(&exportActiveOnly, "active-only", false, "Export only the versions active in the current directory")
+	exportMiseCmd.Flags().BoolVar(&exportExact, "exact", false, "Write the resolved versions (e.g., 20.10.0) of the requested versions")
+	exportMiseCmd.Flags().BoolVar(&exportRequested, "requested", false, "Write the requested version specs (e.g., 20) (default)")
+	exportMiseCmd.MarkFlagsMutuallyExclusive("exact", "requested")
 }
 
 func runExportBrew(cmd *cobra.Command, args []string) error {
@@ -93,17 +119,25 @@ func runExportBrew(cmd *cobra.Command, args []string) error {
 		Dir:     exportDir,
 		DryRun:  !exportApply,
 		Verbose: exportVerbose,
+		Format:  exportBrewFormat,
 	}
 
 	return brew.Export(cfg, opts)
 }
 
 func runExportMise(cmd *cobra.Command, args []string) error {
+	versionMode := mise.VersionRequested
+	if exportExact {
+		versionMode = mise.VersionExact
+	}
+
 	opts := mise.ExportOptions{
-		Dir:     exportDir,
-		DryRun:  !exportApply,
-		Verbose: exportVerbose,
-		Format:  exportMiseFormat,
+		Dir:         exportDir,
+		DryRun:      !exportApply,
+		Verbose:     exportVerbose,
+		Format:      exportMiseFormat,
+		ActiveOnly:  exportActiveOnly,
+		VersionMode: versionMode,
 	}
 
 	return mise.Export(opts)
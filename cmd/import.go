@@ -22,7 +22,37 @@ var importBrewCmd = &cobra.Command{
 	Long: `Import a Homebrew environment from exported files.
 
 Reads the files created by 'goodbye export brew' and installs
-the packages on the current system.`,
+the packages on the current system. Formulas and casks that are
+already installed are marked [installed] and skipped unless
+--reinstall is given.
+
+Each formula name is resolved with brew info first: renamed formulas
+are installed by their current name, and deprecated or disabled ones
+are reported with their replacement. Use --rewrite-names to update the
+export files with the current names.
+
+Each item's outcome is recorded in import-journal.json in the export
+directory. Use --resume to skip items that are already done and retry
+only failed or pending ones.
+
+The output of each item is saved to logs/<type>-<name>.log in the export
+directory, and failures are reported by category (already installed,
+no such formula, keg conflict, checksum mismatch, network error,
+requires macOS version) with suggested fixes.
+
+A Brewfile (brew bundle format) is also accepted. It is used automatically
+when the directory has a Brewfile but no formula list, or explicitly with
+--format brewfile.
+
+Taps are always added before formulas and casks. With --jobs N, formulas
+and casks are installed N at a time and each item's output is printed
+once it finishes. With --batch, names are passed to a single install
+command, falling back to one-by-one installs when the batch fails.
+
+With --prune, formulas and casks installed on request but absent from
+the export are uninstalled after confirmation. Formulas that other
+installed packages depend on (brew uses --installed) and packages in
+[brew.prune] keep in ~/.goodbye.toml are left alone.`,
 	Example: `  # Dry-run (default) - preview what will be imported
   goodbye import brew --dir ~/goodbye-export
 
@@ -35,8 +65,29 @@ the packages on the current system.`,
   # Import without taps
   goodbye import brew --dir ~/goodbye-export --skip-taps --apply
 
+  # Install only Mac App Store apps (mas.txt)
+  goodbye import brew --dir ~/goodbye-export --only mas --apply
+
+  # Restart the services that were running at export time
+  goodbye import brew --dir ~/goodbye-export --only services --apply
+
+  # Import from a Brewfile
+  goodbye import brew --dir ~/goodbye-export --format brewfile --apply
+
+  # Install 4 formulas/casks at a time
+  goodbye import brew --dir ~/goodbye-export --apply --jobs 4
+
+  # Install all formulas with a single brew install command
+  goodbye import brew --dir ~/goodbye-export --apply --batch
+
+  # Also uninstall packages that are not in the export
+  goodbye import brew --dir ~/goodbye-export --apply --prune
+
   # Continue on errors
-  goodbye import brew --dir ~/goodbye-export --apply --continue`,
+  goodbye import brew --dir ~/goodbye-export --apply --continue
+
+  # Resume an interrupted import (skips items already installed)
+  goodbye import brew --dir ~/goodbye-export --apply --resume`,
 	RunE: runImportBrew,
 }
 
@@ -137,7 +188,14 @@ var (
 	importDotfilesNoBack bool
 	importDotfilesURL    string
 	importDotfilesPath   string
-	importBackupTS      string
+	importBackupTS       string
+	importBrewFormat     string
+	importBrewResume     bool
+	importBrewJobs       int
+	importBrewBatch      bool
+	importBrewPrune      bool
+	importBrewReinstall  bool
+	importBrewRewrite    bool
 )
 
 func init() {
@@ -150,9 +208,16 @@ func init() {
 	importBrewCmd.Flags().StringVar(&importDir, "dir", ".", "Directory containing exported files")
 	importBrewCmd.Flags().BoolVar(&importApply, "apply", false, "Actually perform the import (default is dry-run)")
 	importBrewCmd.Flags().BoolVarP(&importVerbose, "verbose", "v", false, "Verbose output")
-	importBrewCmd.Flags().StringVar(&importOnly, "only", "", "Import only specific type (formula, cask, or tap)")
+	importBrewCmd.Flags().StringVar(&importOnly, "only", "", "Import only specific type (formula, cask, tap, mas, or services)")
 	importBrewCmd.Flags().BoolVar(&importSkipTaps, "skip-taps", false, "Skip importing taps")
 	importBrewCmd.Flags().BoolVar(&importContinue, "continue", false, "Continue on errors")
+	importBrewCmd.Flags().BoolVar(&importBrewResume, "resume", false, "Resume a previous import using the journal")
+	importBrewCmd.Flags().StringVar(&importBrewFormat, "format", "", "Input format (txt or brewfile, default: auto-detect)")
+	importBrewCmd.Flags().IntVar(&importBrewJobs, "jobs", 1, "Number of formulas/casks to install concurrently")
+	importBrewCmd.Flags().BoolVar(&importBrewReinstall, "reinstall", false, "Reinstall formulas and casks that are already installed")
+	importBrewCmd.Flags().BoolVar(&importBrewRewrite, "rewrite-names", false, "Rewrite the export files with the current names of renamed formulas")
+	importBrewCmd.Flags().BoolVar(&importBrewPrune, "prune", false, "Uninstall formulas and casks that are not in the export")
+	importBrewCmd.Flags().BoolVar(&importBrewBatch, "batch", false, "Install many names with a single brew command, falling back to one-by-one on failure")
 
 	importMiseCmd.Flags().StringVar(&importDir, "dir", ".", "Directory containing exported files")
 	importMiseCmd.Flags().BoolVar(&importApply, "apply", false, "Actually perform the import (default is dry-run)")
@@ -182,12 +247,19 @@ func runImportBrew(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := brew.ImportOptions{
-		Dir:      importDir,
-		DryRun:   !importApply,
-		Verbose:  importVerbose,
-		Only:     importOnly,
-		SkipTaps: importSkipTaps,
-		Continue: importContinue,
+		Dir:          importDir,
+		DryRun:       !importApply,
+		Verbose:      importVerbose,
+		Only:         importOnly,
+		SkipTaps:     importSkipTaps,
+		Continue:     importContinue,
+		Format:       importBrewFormat,
+		Resume:       importBrewResume,
+		Jobs:         importBrewJobs,
+		Batch:        importBrewBatch,
+		Prune:        importBrewPrune,
+		Reinstall:    importBrewReinstall,
+		RewriteNames: importBrewRewrite,
 	}
 
 	return brew.Import(cfg, opts)
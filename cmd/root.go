@@ -15,7 +15,7 @@ environment package management step by step.
 
 Key features:
   - Homebrew export / import
-  - Homebrew -> mise gradual migration (brew --mise)
+  - Homebrew -> mise / asdf / uv gradual migration (brew --mise, --asdf, --uv)
   - User-defined commands for flexible retrieval
   - Future extensions (uv, etc.) friendly structure
 
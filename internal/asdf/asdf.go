@@ -0,0 +1,420 @@
+package asdf
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/brew"
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// MigrateOptions represents options for the brew --asdf command
+type MigrateOptions struct {
+	DryRun  bool
+	Verbose bool
+}
+
+// MigrationCandidate represents a tool that can be migrated
+type MigrationCandidate struct {
+	BrewName       string
+	NormalizedName string
+	PluginName     string
+	Version        string
+}
+
+// Migrate performs the brew to asdf migration
+func Migrate(cfg *config.Config, opts MigrateOptions) error {
+	// Step 1: Get Homebrew formula list
+	fmt.Println("Getting Homebrew formula list...")
+	formulas, err := getBrewFormulas(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get brew formulas: %w", err)
+	}
+	fmt.Printf("Found %d formulas\n", len(formulas))
+
+	// Step 2: Get asdf plugin list
+	fmt.Println("Getting asdf plugin list...")
+	plugins, err := getPluginList(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get asdf plugins: %w", err)
+	}
+	fmt.Printf("Found %d plugins in asdf plugin repository\n", len(plugins))
+
+	// Step 3: Find migration candidates
+	candidates := findCandidates(formulas, plugins, cfg)
+
+	// Step 4: Resolve concrete versions (asdf has no implicit latest)
+	var resolved, unresolved []MigrationCandidate
+	for _, c := range candidates {
+		version, err := resolveBrewVersion(cfg, c.BrewName)
+		if err != nil {
+			if opts.Verbose {
+				fmt.Printf("  Could not resolve version for %s: %v\n", c.BrewName, err)
+			}
+			unresolved = append(unresolved, c)
+			continue
+		}
+		c.Version = version
+		resolved = append(resolved, c)
+	}
+	candidates = resolved
+
+	if len(candidates) == 0 {
+		fmt.Println("\nNo migration candidates found.")
+		return nil
+	}
+
+	toolVersionsPath, err := expandTilde(toolVersionsFile(cfg))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nFound %d migration candidates:\n", len(candidates))
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-25s %-20s %-12s %s\n", "BREW", "NORMALIZED", "ASDF", "VERSION")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, c := range candidates {
+		fmt.Printf("%-25s %-20s %-12s %s\n", c.BrewName, c.NormalizedName, c.PluginName, c.Version)
+	}
+	fmt.Println(strings.Repeat("-", 70))
+	if len(unresolved) > 0 {
+		fmt.Printf("Skipped %d candidates without a resolvable brew version:\n", len(unresolved))
+		for _, c := range unresolved {
+			fmt.Printf("  - %s\n", c.BrewName)
+		}
+	}
+
+	if opts.DryRun {
+		fmt.Println("\n[dry-run] Would perform the following actions:")
+		for _, c := range candidates {
+			fmt.Printf("  1. asdf plugin add %s (if missing)\n", c.PluginName)
+			fmt.Printf("  2. asdf install %s %s\n", c.PluginName, c.Version)
+			fmt.Printf("  3. Set %s %s in %s\n", c.PluginName, c.Version, toolVersionsPath)
+			fmt.Printf("  4. Verify installation\n")
+			fmt.Printf("  5. brew uninstall %s\n", c.BrewName)
+			fmt.Println()
+		}
+		fmt.Println("\nTo apply these changes, run with --apply")
+		return nil
+	}
+
+	// Step 5: Confirm
+	fmt.Print("\nDo you want to proceed with migration? [y/N]: ")
+	var response string
+	fmt.Scanln(&response)
+	if strings.ToLower(response) != "y" {
+		fmt.Println("Migration cancelled.")
+		return nil
+	}
+
+	installedPlugins, err := getInstalledPlugins(cfg)
+	if err != nil && opts.Verbose {
+		fmt.Printf("Warning: failed to list installed asdf plugins: %v\n", err)
+	}
+
+	// Step 6-10: Migrate each candidate
+	var succeeded, failed []MigrationCandidate
+	for _, c := range candidates {
+		fmt.Printf("\nMigrating %s -> %s %s\n", c.BrewName, c.PluginName, c.Version)
+
+		// Add plugin
+		if !installedPlugins[c.PluginName] {
+			fmt.Printf("  Adding asdf plugin %s...\n", c.PluginName)
+			pluginAddCmd := cfg.Asdf.Commands.PluginAddCmd
+			if pluginAddCmd == "" {
+				pluginAddCmd = "asdf plugin add %s"
+			}
+			if err := runCommand(fmt.Sprintf(pluginAddCmd, c.PluginName), opts.Verbose); err != nil {
+				fmt.Printf("  Failed to add plugin: %v\n", err)
+				failed = append(failed, c)
+				continue
+			}
+			installedPlugins[c.PluginName] = true
+		}
+
+		// Install with asdf
+		fmt.Printf("  Installing %s %s with asdf...\n", c.PluginName, c.Version)
+		installCmd := cfg.Asdf.Commands.InstallCmd
+		if installCmd == "" {
+			installCmd = "asdf install %s %s"
+		}
+		if err := runCommand(fmt.Sprintf(installCmd, c.PluginName, c.Version), opts.Verbose); err != nil {
+			fmt.Printf("  Failed to install: %v\n", err)
+			failed = append(failed, c)
+			continue
+		}
+
+		// Write .tool-versions
+		fmt.Printf("  Updating %s...\n", toolVersionsPath)
+		if err := updateToolVersionsFile(toolVersionsPath, c.PluginName, c.Version); err != nil {
+			fmt.Printf("  Failed to update %s: %v\n", toolVersionsPath, err)
+			failed = append(failed, c)
+			continue
+		}
+
+		// Verify installation
+		fmt.Printf("  Verifying installation...\n")
+		if err := verifyInstallation(cfg, c.PluginName, c.Version); err != nil {
+			fmt.Printf("  Verification failed: %v\n", err)
+			failed = append(failed, c)
+			continue
+		}
+
+		// Uninstall from brew
+		fmt.Printf("  Uninstalling %s from brew...\n", c.BrewName)
+		brewUninstallCmd := cfg.Asdf.Commands.BrewUninstallCmd
+		if brewUninstallCmd == "" {
+			brewUninstallCmd = "brew uninstall %s"
+		}
+		if err := runCommand(fmt.Sprintf(brewUninstallCmd, c.BrewName), opts.Verbose); err != nil {
+			fmt.Printf("  Warning: Failed to uninstall from brew: %v\n", err)
+			// Still consider it a success since asdf is working
+		}
+
+		fmt.Printf("  Successfully migrated %s!\n", c.BrewName)
+		succeeded = append(succeeded, c)
+	}
+
+	// Summary
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("Migration Summary")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("Succeeded: %d\n", len(succeeded))
+	for _, c := range succeeded {
+		fmt.Printf("  - %s -> %s %s\n", c.BrewName, c.PluginName, c.Version)
+	}
+	if len(failed) > 0 {
+		fmt.Printf("Failed: %d\n", len(failed))
+		for _, c := range failed {
+			fmt.Printf("  - %s\n", c.BrewName)
+		}
+	}
+
+	return nil
+}
+
+func getBrewFormulas(cfg *config.Config) ([]string, error) {
+	// Use command from config, fallback to default
+	cmdStr := cfg.Brew.Export.FormulaCmd
+	if cmdStr == "" {
+		cmdStr = "brew list --installed-on-request"
+	}
+	return runCommandLines(cmdStr)
+}
+
+// getPluginList returns every plugin known to the asdf plugin repository
+func getPluginList(cfg *config.Config) (map[string]string, error) {
+	// Use command from config, fallback to default
+	cmdStr := cfg.Asdf.Commands.PluginListAllCmd
+	if cmdStr == "" {
+		cmdStr = "asdf plugin list all"
+	}
+
+	lines, err := runCommandLines(cmdStr)
+	if err != nil {
+		return nil, fmt.Errorf("asdf command failed (is asdf installed?): %w", err)
+	}
+	return parsePluginList(lines), nil
+}
+
+// getInstalledPlugins returns the plugins that are already added locally
+func getInstalledPlugins(cfg *config.Config) (map[string]bool, error) {
+	installed := make(map[string]bool)
+
+	cmdStr := cfg.Asdf.Commands.PluginListCmd
+	if cmdStr == "" {
+		cmdStr = "asdf plugin list"
+	}
+
+	lines, err := runCommandLines(cmdStr)
+	if err != nil {
+		return installed, err
+	}
+	for name := range parsePluginList(lines) {
+		installed[name] = true
+	}
+	return installed, nil
+}
+
+// parsePluginList parses "asdf plugin list [all]" output
+// (format: "name  https://github.com/..." or just "name")
+func parsePluginList(lines []string) map[string]string {
+	plugins := make(map[string]string)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// "asdf plugin list all" marks locally added plugins with "*"
+		name := strings.TrimPrefix(fields[0], "*")
+		if name == "" {
+			continue
+		}
+		plugins[strings.ToLower(name)] = name
+	}
+	return plugins
+}
+
+func normalizeFormulaName(name string) string {
+	// Remove version suffix (e.g., python@3.12 -> python)
+	re := regexp.MustCompile(`@[\d.]+$`)
+	normalized := re.ReplaceAllString(name, "")
+
+	// Convert to lowercase
+	normalized = strings.ToLower(normalized)
+
+	return normalized
+}
+
+func findCandidates(formulas []string, plugins map[string]string, cfg *config.Config) []MigrationCandidate {
+	var candidates []MigrationCandidate
+
+	knownMappings := cfg.Asdf.KnownMappings
+
+	for _, formula := range formulas {
+		normalized := normalizeFormulaName(formula)
+
+		// Check known mappings first
+		if pluginName, ok := knownMappings[normalized]; ok {
+			if _, exists := plugins[pluginName]; exists {
+				candidates = append(candidates, MigrationCandidate{
+					BrewName:       formula,
+					NormalizedName: normalized,
+					PluginName:     pluginName,
+				})
+				continue
+			}
+		}
+
+		// Check direct match in plugin list
+		if pluginName, exists := plugins[normalized]; exists {
+			candidates = append(candidates, MigrationCandidate{
+				BrewName:       formula,
+				NormalizedName: normalized,
+				PluginName:     pluginName,
+			})
+		}
+	}
+
+	return candidates
+}
+
+// resolveBrewVersion returns the installed brew version of a formula
+func resolveBrewVersion(cfg *config.Config, formula string) (string, error) {
+	cmdTemplate := cfg.Asdf.Commands.BrewVersionsCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "brew list --versions %s"
+	}
+
+	lines, err := runCommandLines(fmt.Sprintf(cmdTemplate, formula))
+	if err != nil {
+		return "", err
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no installed version found")
+	}
+	return brew.NewestKegVersion(lines[0])
+}
+
+// updateToolVersions sets the version for a plugin in .tool-versions content
+func updateToolVersions(content, plugin, version string) string {
+	var lines []string
+	replaced := false
+
+	if content != "" {
+		lines = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	}
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == plugin {
+			lines[i] = fmt.Sprintf("%s %s", plugin, version)
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, fmt.Sprintf("%s %s", plugin, version))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func updateToolVersionsFile(path, plugin, version string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updated := updateToolVersions(string(content), plugin, version)
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+func toolVersionsFile(cfg *config.Config) string {
+	if cfg.Asdf.ToolVersionsFile != "" {
+		return cfg.Asdf.ToolVersionsFile
+	}
+	return "~/.tool-versions"
+}
+
+func verifyInstallation(cfg *config.Config, pluginName, version string) error {
+	// Use command from config, fallback to default
+	cmdTemplate := cfg.Asdf.Commands.CurrentCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "asdf current"
+	}
+
+	// Build command with pluginName argument
+	cmdStr := fmt.Sprintf("%s %s", cmdTemplate, pluginName)
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(output), version) {
+		return fmt.Errorf("asdf current does not report %s %s", pluginName, version)
+	}
+	return nil
+}
+
+func runCommand(cmdStr string, verbose bool) error {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	if verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	return cmd.Run()
+}
+
+func runCommandLines(cmdStr string) ([]string, error) {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// expandTilde expands ~ to user's home directory
+func expandTilde(path string) (string, error) {
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, path[1:]), nil
+}
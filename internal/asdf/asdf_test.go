@@ -0,0 +1,180 @@
+package asdf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestParsePluginList(t *testing.T) {
+	lines := []string{
+		"golang    https://github.com/asdf-community/asdf-golang.git",
+		"*nodejs   https://github.com/asdf-vm/asdf-nodejs.git",
+		"python",
+	}
+
+	expected := map[string]string{
+		"golang": "golang",
+		"nodejs": "nodejs",
+		"python": "python",
+	}
+
+	result := parsePluginList(lines)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parsePluginList() = %v, want %v", result, expected)
+	}
+}
+
+func TestFindCandidates(t *testing.T) {
+	plugins := map[string]string{
+		"nodejs":    "nodejs",
+		"golang":    "golang",
+		"python":    "python",
+		"terraform": "terraform",
+	}
+
+	tests := []struct {
+		name     string
+		formulas []string
+		expected []MigrationCandidate
+	}{
+		{
+			name:     "empty formulas",
+			formulas: []string{},
+			expected: nil,
+		},
+		{
+			name:     "no matching formulas",
+			formulas: []string{"vim", "tmux"},
+			expected: nil,
+		},
+		{
+			name:     "known mapping - node to nodejs",
+			formulas: []string{"node"},
+			expected: []MigrationCandidate{
+				{BrewName: "node", NormalizedName: "node", PluginName: "nodejs"},
+			},
+		},
+		{
+			name:     "known mapping - go to golang",
+			formulas: []string{"go"},
+			expected: []MigrationCandidate{
+				{BrewName: "go", NormalizedName: "go", PluginName: "golang"},
+			},
+		},
+		{
+			name:     "formula with version suffix",
+			formulas: []string{"python@3.12"},
+			expected: []MigrationCandidate{
+				{BrewName: "python@3.12", NormalizedName: "python", PluginName: "python"},
+			},
+		},
+		{
+			name:     "direct plugin match",
+			formulas: []string{"terraform"},
+			expected: []MigrationCandidate{
+				{BrewName: "terraform", NormalizedName: "terraform", PluginName: "terraform"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := findCandidates(tt.formulas, plugins, config.DefaultConfig())
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("findCandidates() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateToolVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		plugin   string
+		version  string
+		expected string
+	}{
+		{
+			name:     "empty file",
+			content:  "",
+			plugin:   "nodejs",
+			version:  "20.10.0",
+			expected: "nodejs 20.10.0\n",
+		},
+		{
+			name:     "append new plugin",
+			content:  "python 3.12.1\n",
+			plugin:   "nodejs",
+			version:  "20.10.0",
+			expected: "python 3.12.1\nnodejs 20.10.0\n",
+		},
+		{
+			name:     "replace existing plugin",
+			content:  "# comment\nnodejs 18.19.0\npython 3.12.1\n",
+			plugin:   "nodejs",
+			version:  "20.10.0",
+			expected: "# comment\nnodejs 20.10.0\npython 3.12.1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := updateToolVersions(tt.content, tt.plugin, tt.version)
+			if result != tt.expected {
+				t.Errorf("updateToolVersions() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateToolVersionsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".tool-versions")
+
+	if err := updateToolVersionsFile(path, "nodejs", "20.10.0"); err != nil {
+		t.Fatalf("updateToolVersionsFile() error = %v", err)
+	}
+	if err := updateToolVersionsFile(path, "golang", "1.21.5"); err != nil {
+		t.Fatalf("updateToolVersionsFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	expected := "nodejs 20.10.0\ngolang 1.21.5\n"
+	if string(content) != expected {
+		t.Errorf("content = %q, want %q", string(content), expected)
+	}
+}
+
+func TestResolveBrewVersionWithCustomCommand(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Asdf.Commands.BrewVersionsCmd = "echo %s 1.0.0 1.1.0_2"
+
+	version, err := resolveBrewVersion(cfg, "jq")
+	if err != nil {
+		t.Fatalf("resolveBrewVersion() error = %v", err)
+	}
+	if version != "1.1.0" {
+		t.Errorf("resolveBrewVersion() = %q, want %q", version, "1.1.0")
+	}
+}
+
+func TestResolveBrewVersionKegsOutOfOrder(t *testing.T) {
+	cfg := config.DefaultConfig()
+	// brew lists kegs in directory order, so the newest one may come first
+	cfg.Asdf.Commands.BrewVersionsCmd = "echo %s 1.10.0 1.9.0_2"
+
+	version, err := resolveBrewVersion(cfg, "jq")
+	if err != nil {
+		t.Fatalf("resolveBrewVersion() error = %v", err)
+	}
+	if version != "1.10.0" {
+		t.Errorf("resolveBrewVersion() = %q, want %q", version, "1.10.0")
+	}
+}
@@ -0,0 +1,267 @@
+package brew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// Formula classes reported by Analyze
+const (
+	ClassLeaf       = "leaf"       // nothing installed depends on it
+	ClassDependency = "dependency" // other installed formulas depend on it
+	ClassOrphan     = "orphan"     // installed as a dependency that nothing needs anymore
+)
+
+// AnalyzeOptions represents options for the analyze command
+type AnalyzeOptions struct {
+	Dir     string // export directory whose formula list is rewritten with --apply
+	DryRun  bool
+	Verbose bool
+	JSON    bool
+}
+
+// FormulaAnalysis represents the classification of a single installed formula
+type FormulaAnalysis struct {
+	Name       string   `json:"name"`
+	Class      string   `json:"class"`
+	Requested  bool     `json:"requested"`
+	Dependents []string `json:"dependents"`
+}
+
+// AnalyzeResult represents the installed dependency graph analysis
+type AnalyzeResult struct {
+	Formulae []FormulaAnalysis `json:"formulae"`
+	// Redundant lists requested formulas that only act as dependencies
+	Redundant []string `json:"redundant"`
+	// Suggested is the trimmed formula list (requested leaves)
+	Suggested []string `json:"suggested"`
+}
+
+// Analyze classifies installed formulas as leaves, dependencies or orphans
+// and suggests a formula list without requested formulas that are only
+// used as dependencies
+func Analyze(cfg *config.Config, opts AnalyzeOptions, w io.Writer) error {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+
+	// Expand ~ to home directory
+	if strings.HasPrefix(opts.Dir, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.Dir = filepath.Join(homeDir, opts.Dir[1:])
+	}
+
+	depsCmd := cfg.Brew.Export.DepsCmd
+	if depsCmd == "" {
+		depsCmd = "brew deps --installed --json"
+	}
+	lines, err := runCommand(depsCmd)
+	if err != nil {
+		return fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	graph, err := parseDeps(strings.Join(lines, "\n"))
+	if err != nil {
+		return fmt.Errorf("failed to parse dependencies: %w", err)
+	}
+
+	requested, err := runCommand(cfg.Brew.Export.FormulaCmd)
+	if err != nil {
+		return fmt.Errorf("failed to get formulas: %w", err)
+	}
+
+	result := analyzeGraph(graph, requested)
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		printAnalysis(w, result, opts.Verbose)
+	}
+
+	if len(result.Redundant) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(opts.Dir, formulaFile(cfg))
+	if opts.DryRun {
+		if !opts.JSON {
+			fmt.Fprintf(w, "\nRun with --apply to remove them from %s\n", path)
+		}
+		return nil
+	}
+
+	removed, err := trimFormulaFile(path, result.Redundant)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nRemoved %d formulas from %s\n", removed, path)
+	return nil
+}
+
+// parseDeps parses the installed dependency graph (formula -> dependencies).
+// It accepts a JSON object keyed by formula, a JSON array of formula objects
+// with "name" and "dependencies" (as in brew info --json), or the plain
+// "formula: dep1 dep2" lines printed by brew deps --installed.
+func parseDeps(output string) (map[string][]string, error) {
+	graph := make(map[string][]string)
+	trimmed := strings.TrimSpace(output)
+
+	switch {
+	case strings.HasPrefix(trimmed, "{"):
+		var byName map[string][]string
+		if err := json.Unmarshal([]byte(trimmed), &byName); err == nil {
+			return byName, nil
+		}
+		var info Info
+		if err := json.Unmarshal([]byte(trimmed), &info); err != nil {
+			return nil, err
+		}
+		for _, f := range info.Formulae {
+			graph[f.Name] = f.Dependencies
+		}
+	case strings.HasPrefix(trimmed, "["):
+		var formulae []FormulaInfo
+		if err := json.Unmarshal([]byte(trimmed), &formulae); err != nil {
+			return nil, err
+		}
+		for _, f := range formulae {
+			graph[f.Name] = f.Dependencies
+		}
+	default:
+		for _, line := range strings.Split(trimmed, "\n") {
+			name, deps, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			graph[strings.TrimSpace(name)] = strings.Fields(deps)
+		}
+	}
+	return graph, nil
+}
+
+// analyzeGraph classifies every formula in the graph
+func analyzeGraph(graph map[string][]string, requested []string) *AnalyzeResult {
+	isRequested := make(map[string]bool)
+	for _, name := range requested {
+		isRequested[name] = true
+	}
+
+	dependents := make(map[string][]string)
+	for name, deps := range graph {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &AnalyzeResult{Formulae: []FormulaAnalysis{}, Redundant: []string{}, Suggested: []string{}}
+	for _, name := range names {
+		users := dependents[name]
+		sort.Strings(users)
+
+		a := FormulaAnalysis{Name: name, Requested: isRequested[name], Dependents: users}
+		switch {
+		case len(users) > 0:
+			a.Class = ClassDependency
+			if a.Requested {
+				result.Redundant = append(result.Redundant, name)
+			}
+		case a.Requested:
+			a.Class = ClassLeaf
+			result.Suggested = append(result.Suggested, name)
+		default:
+			a.Class = ClassOrphan
+		}
+		if a.Dependents == nil {
+			a.Dependents = []string{}
+		}
+		result.Formulae = append(result.Formulae, a)
+	}
+	return result
+}
+
+func printAnalysis(w io.Writer, result *AnalyzeResult, verbose bool) {
+	fmt.Fprintf(w, "%-28s %-11s %-10s %s\n", "FORMULA", "CLASS", "REQUESTED", "USED BY")
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+	for _, a := range result.Formulae {
+		// Unrequested dependencies are expected; show them only in verbose mode
+		if !verbose && a.Class == ClassDependency && !a.Requested {
+			continue
+		}
+		requested := ""
+		if a.Requested {
+			requested = "yes"
+		}
+		fmt.Fprintf(w, "%-28s %-11s %-10s %s\n", a.Name, a.Class, requested, truncateList(a.Dependents, 3))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+
+	var orphans []string
+	for _, a := range result.Formulae {
+		if a.Class == ClassOrphan {
+			orphans = append(orphans, a.Name)
+		}
+	}
+	if len(orphans) > 0 {
+		fmt.Fprintf(w, "\nOrphans (no longer needed, see 'brew autoremove'): %s\n", strings.Join(orphans, ", "))
+	}
+
+	if len(result.Redundant) == 0 {
+		fmt.Fprintln(w, "\nNo requested formulas act only as dependencies.")
+		return
+	}
+	fmt.Fprintln(w, "\nRequested formulas only used as dependencies:")
+	for _, a := range result.Formulae {
+		if a.Class == ClassDependency && a.Requested {
+			fmt.Fprintf(w, "  - %s (used by %s)\n", a.Name, strings.Join(a.Dependents, ", "))
+		}
+	}
+	fmt.Fprintf(w, "\nSuggested formula list (%d items): %s\n", len(result.Suggested), truncateList(result.Suggested, 10))
+}
+
+// trimFormulaFile removes the given formulas from an exported formula list,
+// keeping comments and the order of the remaining lines
+func trimFormulaFile(path string, remove []string) (int, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	drop := make(map[string]bool)
+	for _, name := range remove {
+		drop[name] = true
+	}
+
+	var kept []string
+	removed := 0
+	for _, line := range lines {
+		if drop[bareName(strings.TrimSpace(line))] {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	if err := writeLines(path, kept); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,122 @@
+package brew
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestParseDeps(t *testing.T) {
+	expected := map[string][]string{
+		"wget":    {"libidn2", "openssl@3"},
+		"libidn2": {"libunistring"},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"json object", `{"wget": ["libidn2", "openssl@3"], "libidn2": ["libunistring"]}`},
+		{"json array", `[{"name": "wget", "dependencies": ["libidn2", "openssl@3"]}, {"name": "libidn2", "dependencies": ["libunistring"]}]`},
+		{"brew info", `{"formulae": [{"name": "wget", "dependencies": ["libidn2", "openssl@3"]}, {"name": "libidn2", "dependencies": ["libunistring"]}]}`},
+		{"text", "wget: libidn2 openssl@3\nlibidn2: libunistring\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseDeps(tt.output)
+			if err != nil {
+				t.Fatalf("parseDeps() error = %v", err)
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("parseDeps() = %v, want %v", result, expected)
+			}
+		})
+	}
+}
+
+func TestAnalyzeGraph(t *testing.T) {
+	graph := map[string][]string{
+		"wget":            {"openssl@3"},
+		"openssl@3":       {"ca-certificates"},
+		"ca-certificates": {},
+		"jq":              {"oniguruma"},
+		"oniguruma":       {},
+		"libyaml":         {},
+	}
+	requested := []string{"wget", "openssl@3", "jq"}
+
+	result := analyzeGraph(graph, requested)
+
+	classes := make(map[string]string)
+	for _, a := range result.Formulae {
+		classes[a.Name] = a.Class
+	}
+	expectedClasses := map[string]string{
+		"wget":            ClassLeaf,
+		"openssl@3":       ClassDependency,
+		"ca-certificates": ClassDependency,
+		"jq":              ClassLeaf,
+		"oniguruma":       ClassDependency,
+		"libyaml":         ClassOrphan,
+	}
+	if !reflect.DeepEqual(classes, expectedClasses) {
+		t.Errorf("classes = %v, want %v", classes, expectedClasses)
+	}
+	if !reflect.DeepEqual(result.Redundant, []string{"openssl@3"}) {
+		t.Errorf("Redundant = %v, want [openssl@3]", result.Redundant)
+	}
+	if !reflect.DeepEqual(result.Suggested, []string{"jq", "wget"}) {
+		t.Errorf("Suggested = %v, want [jq wget]", result.Suggested)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tmpDir := t.TempDir()
+	formulaPath := filepath.Join(tmpDir, "formula.txt")
+	if err := os.WriteFile(formulaPath, []byte("# base\nwget\nhomebrew/core/openssl@3\njq\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.DepsCmd = `echo '{"wget": ["openssl@3"], "openssl@3": [], "jq": []}'`
+	cfg.Brew.Export.FormulaCmd = "printf 'wget\\nopenssl@3\\njq\\n'"
+
+	t.Run("dry-run json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Analyze(cfg, AnalyzeOptions{Dir: tmpDir, DryRun: true, JSON: true}, &buf); err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		var result AnalyzeResult
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v", err)
+		}
+		if !reflect.DeepEqual(result.Redundant, []string{"openssl@3"}) {
+			t.Errorf("Redundant = %v", result.Redundant)
+		}
+
+		lines, err := readLines(formulaPath)
+		if err != nil || len(lines) != 4 {
+			t.Errorf("dry-run should not modify formula.txt: %v, %v", lines, err)
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Analyze(cfg, AnalyzeOptions{Dir: tmpDir}, &buf); err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		lines, err := readLines(formulaPath)
+		if err != nil {
+			t.Fatalf("failed to read formula.txt: %v", err)
+		}
+		if expected := []string{"# base", "wget", "jq"}; !reflect.DeepEqual(lines, expected) {
+			t.Errorf("formula.txt = %v, want %v", lines, expected)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package brew
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,16 +17,56 @@ type ExportOptions struct {
 	Dir     string
 	DryRun  bool
 	Verbose bool
+	Format  string // "txt" (default) or "brewfile"
 }
 
 // ImportOptions represents options for the import command
 type ImportOptions struct {
-	Dir      string
-	DryRun   bool
-	Verbose  bool
-	Only     string // formula, cask, or tap
-	SkipTaps bool
-	Continue bool
+	Dir          string
+	DryRun       bool
+	Verbose      bool
+	Only         string // formula, cask, tap, mas, or services
+	SkipTaps     bool
+	Continue     bool
+	Format       string // "txt", "brewfile", or "" to auto-detect
+	Resume       bool   // skip items the journal records as done
+	Jobs         int    // number of concurrent installs (<= 1 installs one at a time)
+	Batch        bool   // install many names with a single command, falling back to one-by-one
+	Prune        bool   // uninstall formulas and casks that are not in the export
+	Reinstall    bool   // reinstall packages that are already installed instead of skipping them
+	RewriteNames bool   // rewrite the export files with current names of renamed formulas
+}
+
+// importItem represents a single package to install
+type importItem struct {
+	Name         string   // install argument (formula, cask, tap, or app ID)
+	Comment      string   // optional description shown next to the name
+	Args         []string // extra install flags placed before the name
+	TrailingArgs []string // extra arguments placed after the name (e.g., tap URL)
+	PostCmds     []string // commands to run after a successful install
+	Installed    bool     // already present on the system (see snapshotInstalled)
+}
+
+// importGroup represents a set of items installed with the same command
+type importGroup struct {
+	Label        string // shown in output (usually the source file name)
+	Type         string // item type used in log file names (tap, formula, cask, mas, service)
+	Cmd          string // install command prefix
+	ReinstallCmd string // command prefix used for installed items with --reinstall
+	Items        []importItem
+	Serial       bool // never install items concurrently (taps, services)
+	Batchable    bool // the command accepts several names at once
+}
+
+// importRun holds the state shared by every group of a single import
+type importRun struct {
+	opts    ImportOptions
+	journal *Journal
+	skipped map[string]int // items skipped per group (done in the journal or already installed)
+	groups  []string       // labels of the groups installed, in order
+
+	toInstall int // packages that would be installed (dry-run)
+	present   int // packages that are already installed
 }
 
 // Export exports the current Homebrew environment to files
@@ -43,16 +84,31 @@ func Export(cfg *config.Config, opts ExportOptions) error {
 		opts.Dir = filepath.Join(homeDir, opts.Dir[1:])
 	}
 
+	switch opts.Format {
+	case "", "txt":
+	case "brewfile":
+		return exportBrewfile(cfg, opts)
+	default:
+		return fmt.Errorf("invalid format: %s (must be txt or brewfile)", opts.Format)
+	}
+
 	if opts.DryRun {
 		fmt.Println("[dry-run] Would create directory:", opts.Dir)
 		fmt.Println("[dry-run] Would execute commands:")
-		fmt.Printf("  formula: %s\n", cfg.Brew.Export.FormulaCmd)
-		fmt.Printf("  cask:    %s\n", cfg.Brew.Export.CaskCmd)
-		fmt.Printf("  tap:     %s\n", cfg.Brew.Export.TapCmd)
+		fmt.Printf("  formula:  %s\n", cfg.Brew.Export.FormulaCmd)
+		fmt.Printf("  cask:     %s\n", cfg.Brew.Export.CaskCmd)
+		fmt.Printf("  tap:      %s\n", cfg.Brew.Export.TapCmd)
+		fmt.Printf("  tap-info: %s\n", cfg.Brew.Export.TapInfoCmd)
+		fmt.Printf("  info:     %s\n", cfg.Brew.Export.InfoCmd)
+		fmt.Printf("  services: %s\n", cfg.Brew.Export.ServicesCmd)
+		fmt.Printf("  mas:      %s\n", cfg.Brew.Export.MasCmd)
 		fmt.Println("[dry-run] Would create files:")
 		fmt.Printf("  %s/formula.txt\n", opts.Dir)
+		fmt.Printf("  %s/formula.json\n", opts.Dir)
 		fmt.Printf("  %s/cask.txt\n", opts.Dir)
 		fmt.Printf("  %s/tap.txt\n", opts.Dir)
+		fmt.Printf("  %s/services.json\n", opts.Dir)
+		fmt.Printf("  %s/%s\n", opts.Dir, masFile)
 
 		// Show what would be exported
 		fmt.Println("\n[dry-run] Preview of export content:")
@@ -78,6 +134,32 @@ func Export(cfg *config.Config, opts ExportOptions) error {
 			fmt.Printf("  tap (%d items): %s\n", len(taps), truncateList(taps, 5))
 		}
 
+		services, err := getServices(cfg.Brew.Export.ServicesCmd)
+		if err != nil {
+			fmt.Printf("  services: (error: %v)\n", err)
+		} else {
+			var running []string
+			for _, svc := range services {
+				if svc.Running() {
+					running = append(running, svc.Name)
+				}
+			}
+			fmt.Printf("  services (%d running): %s\n", len(running), truncateList(running, 5))
+		}
+
+		if cfg.Brew.Export.MasCmd != "" {
+			lines, err := runCommand(cfg.Brew.Export.MasCmd)
+			if err != nil {
+				fmt.Printf("  mas: (error: %v)\n", err)
+			} else {
+				var names []string
+				for _, app := range parseMasList(lines) {
+					names = append(names, app.Name)
+				}
+				fmt.Printf("  mas (%d items): %s\n", len(names), truncateList(names, 5))
+			}
+		}
+
 		return nil
 	}
 
@@ -86,21 +168,34 @@ func Export(cfg *config.Config, opts ExportOptions) error {
 		return fmt.Errorf("failed to create directory %s: %w", opts.Dir, err)
 	}
 
+	// Formula info is used to qualify third-party tap names and for formula.json
+	var info *Info
+	if cfg.Brew.Export.InfoCmd != "" {
+		var err error
+		info, err = getInfo(cfg.Brew.Export.InfoCmd)
+		if err != nil {
+			fmt.Printf("Warning: failed to get formula info, tap names are not qualified and formula.json is skipped: %v\n", err)
+		}
+	}
+
 	// Export formula
 	formulas, err := runCommand(cfg.Brew.Export.FormulaCmd)
 	if err != nil {
 		return fmt.Errorf("failed to get formulas: %w", err)
 	}
+	formulas = qualifyNames(formulas, info, false)
 	if err := writeLines(filepath.Join(opts.Dir, "formula.txt"), formulas); err != nil {
 		return fmt.Errorf("failed to write formula.txt: %w", err)
 	}
 	fmt.Printf("Exported %d formulas to %s/formula.txt\n", len(formulas), opts.Dir)
+	exportFormulaMetadata(opts.Dir, formulas, info)
 
 	// Export cask
 	casks, err := runCommand(cfg.Brew.Export.CaskCmd)
 	if err != nil {
 		return fmt.Errorf("failed to get casks: %w", err)
 	}
+	casks = qualifyNames(casks, info, true)
 	if err := writeLines(filepath.Join(opts.Dir, "cask.txt"), casks); err != nil {
 		return fmt.Errorf("failed to write cask.txt: %w", err)
 	}
@@ -111,11 +206,17 @@ func Export(cfg *config.Config, opts ExportOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to get taps: %w", err)
 	}
-	if err := writeLines(filepath.Join(opts.Dir, "tap.txt"), taps); err != nil {
+	if err := writeLines(filepath.Join(opts.Dir, "tap.txt"), tapLines(taps, exportTapRemotes(cfg, opts.Verbose))); err != nil {
 		return fmt.Errorf("failed to write tap.txt: %w", err)
 	}
 	fmt.Printf("Exported %d taps to %s/tap.txt\n", len(taps), opts.Dir)
 
+	// Export services
+	exportServices(cfg, opts.Dir)
+
+	// Export Mac App Store apps
+	exportMas(cfg, opts.Dir)
+
 	fmt.Println("\nExport completed successfully!")
 	return nil
 }
@@ -140,137 +241,295 @@ func Import(cfg *config.Config, opts ImportOptions) error {
 		return fmt.Errorf("directory does not exist: %s", opts.Dir)
 	}
 
-	var importTaps, importFormulas, importCasks bool
+	var importTaps, importFormulas, importCasks, importMas, importServices bool
 
 	switch opts.Only {
 	case "":
 		importTaps = !opts.SkipTaps
 		importFormulas = true
 		importCasks = true
+		importMas = true
+		importServices = true
 	case "tap":
 		importTaps = true
 	case "formula":
 		importFormulas = true
 	case "cask":
 		importCasks = true
+	case "mas":
+		importMas = true
+	case "services":
+		importServices = true
 	default:
-		return fmt.Errorf("invalid --only value: %s (must be formula, cask, or tap)", opts.Only)
+		return fmt.Errorf("invalid --only value: %s (must be formula, cask, tap, mas, or services)", opts.Only)
+	}
+
+	format, err := resolveImportFormat(cfg, opts)
+	if err != nil {
+		return err
 	}
 
 	if opts.DryRun {
 		fmt.Println("[dry-run] Would import from directory:", opts.Dir)
 	}
 
-	// Import taps first
-	if importTaps {
-		tapCmd := cfg.Brew.Import.TapCmd
-		if tapCmd == "" {
-			tapCmd = "brew tap"
+	var taps, formulas, casks, masApps importGroup
+	if format == "brewfile" {
+		taps, formulas, casks, masApps, err = readBrewfileGroups(cfg, opts.Dir)
+		if err != nil {
+			return err
 		}
-		if err := importFile(opts.Dir, "tap.txt", tapCmd, opts); err != nil {
-			if !opts.Continue {
+	} else {
+		if importTaps {
+			taps, err = readTapGroup(cfg, opts.Dir, opts)
+			if err != nil {
+				return err
+			}
+		}
+		if importFormulas {
+			formulas, err = readImportGroup(opts.Dir, formulaFile(cfg), formulaInstallCmd(cfg), opts)
+			if err != nil {
+				return err
+			}
+			formulas.Type = "formula"
+			formulas.Batchable = true
+			if err := applyFormulaMetadata(cfg, opts.Dir, &formulas, opts); err != nil {
 				return err
 			}
-			fmt.Printf("Warning: %v\n", err)
 		}
+		if importCasks {
+			casks, err = readImportGroup(opts.Dir, caskFile(cfg), caskInstallCmd(cfg), opts)
+			if err != nil {
+				return err
+			}
+			casks.Type = "cask"
+			casks.Batchable = true
+		}
+		if importMas {
+			masApps, err = readMasGroup(cfg, opts.Dir, opts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if importMas {
+		skipInstalledMas(cfg, &masApps, opts)
 	}
+	// Prune compares against the names as exported, since resolution drops
+	// disabled formulas that may still be installed
+	exportedFormulas := formulas
+	exportedFormulas.Items = append([]importItem(nil), formulas.Items...)
+	exportedCasks := importCasks
 
-	// Import formulas
+	var resolution formulaResolution
 	if importFormulas {
-		// Use filename from config, fallback to default
-		formulaFile := cfg.Brew.Import.FormulaFile
-		if formulaFile == "" {
-			formulaFile = "formula.txt"
-		}
-		formulaInstallCmd := cfg.Brew.Import.FormulaInstallCmd
-		if formulaInstallCmd == "" {
-			formulaInstallCmd = "brew install"
-		}
-		if err := importFile(opts.Dir, formulaFile, formulaInstallCmd, opts); err != nil {
-			if !opts.Continue {
+		resolution = resolveFormulas(cfg, &formulas, opts)
+		if opts.RewriteNames {
+			if err := rewriteFormulaNames(cfg, opts.Dir, format, resolution.Renames, opts); err != nil {
 				return err
 			}
-			fmt.Printf("Warning: %v\n", err)
 		}
 	}
 
-	// Import casks
-	if importCasks {
-		// Use filename from config, fallback to default
-		caskFile := cfg.Brew.Import.CaskFile
-		if caskFile == "" {
-			caskFile = "cask.txt"
+	// Formulas migrated to casks are installed as casks
+	if len(resolution.MovedToCask) > 0 {
+		if casks.Label == "" {
+			casks = importGroup{Label: "casks (migrated from formulas)", Type: "cask", Cmd: caskInstallCmd(cfg), Batchable: true}
+		}
+		casks.Items = append(casks.Items, resolution.MovedToCask...)
+		importCasks = true
+	}
+	snapshotInstalled(cfg, &formulas, &casks, opts)
+
+	// Tap the repositories of fully-qualified user/repo/formula entries that
+	// tap.txt does not cover (missing file or --skip-taps)
+	if implied := impliedTaps(taps, formulas, casks); len(implied) > 0 {
+		if taps.Label == "" {
+			taps = importGroup{Label: "taps (from formula names)", Type: "tap", Cmd: tapInstallCmd(cfg), Serial: true}
 		}
-		caskInstallCmd := cfg.Brew.Import.CaskInstallCmd
-		if caskInstallCmd == "" {
-			caskInstallCmd = "brew install --cask"
+		taps.Items = append(taps.Items, implied...)
+		importTaps = true
+	}
+
+	run, err := newImportRun(opts)
+	if err != nil {
+		return err
+	}
+
+	// Import taps first, then formulas, casks and Mac App Store apps
+	var groups []importGroup
+	for _, g := range []struct {
+		enabled bool
+		group   importGroup
+	}{
+		{importTaps, taps},
+		{importFormulas, formulas},
+		{importCasks, casks},
+		{importMas, masApps},
+	} {
+		if g.enabled {
+			groups = append(groups, g.group)
 		}
-		if err := importFile(opts.Dir, caskFile, caskInstallCmd, opts); err != nil {
+	}
+	installErr := run.installGroups(groups)
+
+	// Restart services once their formulas are installed
+	if installErr == nil && importServices {
+		serviceGroups, err := readServiceGroups(cfg, opts)
+		if err != nil {
 			if !opts.Continue {
 				return err
 			}
 			fmt.Printf("Warning: %v\n", err)
 		}
+		installErr = run.installGroups(serviceGroups)
 	}
 
-	if !opts.DryRun {
-		fmt.Println("\nImport completed!")
+	// Remove packages missing from the export once everything is installed
+	if installErr == nil && opts.Prune {
+		var pruneFormulas, pruneCasks *importGroup
+		if importFormulas {
+			pruneFormulas = exportedGroup(withItems(exportedFormulas, formulas.Items))
+		}
+		if exportedCasks {
+			pruneCasks = exportedGroup(casks)
+		}
+		if pruneFormulas != nil || pruneCasks != nil {
+			installErr = prune(cfg, opts, pruneFormulas, pruneCasks)
+		}
 	}
+
+	if opts.DryRun {
+		fmt.Printf("\n[dry-run] %d to install, %d already present", run.toInstall, run.present)
+		if opts.Reinstall && run.present > 0 {
+			fmt.Print(" (will be reinstalled)")
+		}
+		fmt.Println()
+		printUnresolved(resolution.Unresolved)
+		return installErr
+	}
+
+	printImportSummary(run.journal, run.groups, run.skipped)
+	printUnresolved(resolution.Unresolved)
+	if installErr != nil {
+		return installErr
+	}
+
+	fmt.Println("\nImport completed!")
 	return nil
 }
 
-func importFile(dir, filename, cmdPrefix string, opts ImportOptions) error {
+// newImportRun prepares the journal for an import.
+// Without --resume, a fresh journal replaces any previous one.
+func newImportRun(opts ImportOptions) (*importRun, error) {
+	run := &importRun{
+		opts:    opts,
+		skipped: make(map[string]int),
+	}
+
+	path := filepath.Join(opts.Dir, journalFile)
+	if opts.Resume {
+		journal, err := loadJournal(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load journal: %w", err)
+		}
+		run.journal = journal
+	} else {
+		run.journal = newJournal(path)
+	}
+	return run, nil
+}
+
+// readImportGroup reads an exported list file into an import group.
+// A missing file results in an empty group.
+func readImportGroup(dir, filename, cmdPrefix string, opts ImportOptions) (importGroup, error) {
+	group := importGroup{Label: filename, Cmd: cmdPrefix}
 	filePath := filepath.Join(dir, filename)
 
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		if opts.Verbose {
 			fmt.Printf("Skipping %s (file not found)\n", filename)
 		}
-		return nil
+		return group, nil
 	}
 
 	lines, err := readLines(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read %s: %w", filename, err)
+		return group, fmt.Errorf("failed to read %s: %w", filename, err)
 	}
 
-	if len(lines) == 0 {
-		if opts.Verbose {
-			fmt.Printf("Skipping %s (empty)\n", filename)
-		}
-		return nil
-	}
-
-	fmt.Printf("\n%s (%d items):\n", filename, len(lines))
-
 	for _, item := range lines {
 		item = strings.TrimSpace(item)
 		if item == "" || strings.HasPrefix(item, "#") {
 			continue
 		}
+		group.Items = append(group.Items, importItem{Name: item})
+	}
+	return group, nil
+}
 
-		cmd := fmt.Sprintf("%s %s", cmdPrefix, item)
+// resolveImportFormat determines the import format, auto-detecting a
+// Brewfile when no formula list exists in the directory
+func resolveImportFormat(cfg *config.Config, opts ImportOptions) (string, error) {
+	switch opts.Format {
+	case "txt", "brewfile":
+		return opts.Format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("invalid format: %s (must be txt or brewfile)", opts.Format)
+	}
 
-		if opts.DryRun {
-			fmt.Printf("  [dry-run] %s\n", cmd)
-			continue
-		}
+	if _, err := os.Stat(filepath.Join(opts.Dir, formulaFile(cfg))); err == nil {
+		return "txt", nil
+	}
+	if _, err := os.Stat(filepath.Join(opts.Dir, brewfileName)); err == nil {
+		return "brewfile", nil
+	}
+	return "txt", nil
+}
 
-		if opts.Verbose {
-			fmt.Printf("  Running: %s\n", cmd)
-		}
+func tapInstallCmd(cfg *config.Config) string {
+	if cfg.Brew.Import.TapCmd != "" {
+		return cfg.Brew.Import.TapCmd
+	}
+	return "brew tap"
+}
 
-		if err := runCommandExec(cmd); err != nil {
-			if opts.Continue {
-				fmt.Printf("  Error installing %s: %v (continuing...)\n", item, err)
-				continue
-			}
-			return fmt.Errorf("failed to run '%s': %w", cmd, err)
-		}
-		fmt.Printf("  Installed: %s\n", item)
+func formulaFile(cfg *config.Config) string {
+	// Use filename from config, fallback to default
+	if cfg.Brew.Import.FormulaFile != "" {
+		return cfg.Brew.Import.FormulaFile
 	}
+	return "formula.txt"
+}
 
-	return nil
+func formulaInstallCmd(cfg *config.Config) string {
+	if cfg.Brew.Import.FormulaInstallCmd != "" {
+		return cfg.Brew.Import.FormulaInstallCmd
+	}
+	return "brew install"
+}
+
+func caskFile(cfg *config.Config) string {
+	// Use filename from config, fallback to default
+	if cfg.Brew.Import.CaskFile != "" {
+		return cfg.Brew.Import.CaskFile
+	}
+	return "cask.txt"
+}
+
+func caskInstallCmd(cfg *config.Config) string {
+	if cfg.Brew.Import.CaskInstallCmd != "" {
+		return cfg.Brew.Import.CaskInstallCmd
+	}
+	return "brew install --cask"
+}
+
+func masInstallCmd(cfg *config.Config) string {
+	if cfg.Brew.Import.MasInstallCmd != "" {
+		return cfg.Brew.Import.MasInstallCmd
+	}
+	return "mas install"
 }
 
 func runCommand(cmdStr string) ([]string, error) {
@@ -292,9 +551,13 @@ func runCommand(cmdStr string) ([]string, error) {
 }
 
 func runCommandExec(cmdStr string) error {
+	return runCommandTo(cmdStr, os.Stdout, os.Stderr)
+}
+
+func runCommandTo(cmdStr string, stdout, stderr io.Writer) error {
 	cmd := exec.Command("sh", "-c", cmdStr)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	return cmd.Run()
 }
 
@@ -9,6 +9,18 @@ import (
 	"github.com/yyYank/goodbye/internal/config"
 )
 
+// testImportConfig returns the default config with the list and info
+// commands Import runs (even in dry-run) replaced by fakes, so tests do not
+// depend on the packages installed on the host
+func testImportConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.FormulaListCmd = "true"
+	cfg.Brew.Import.CaskListCmd = "true"
+	cfg.Brew.Import.MasListCmd = ""
+	cfg.Brew.Import.InfoCmd = ""
+	return cfg
+}
+
 func TestTruncateList(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -200,7 +212,7 @@ func TestImportOptionsValidation(t *testing.T) {
 				DryRun: true,
 				Only:   tt.only,
 			}
-			err := Import(config.DefaultConfig(), opts)
+			err := Import(testImportConfig(), opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Import() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -213,7 +225,7 @@ func TestImportNonExistentDirectory(t *testing.T) {
 		Dir:    "/nonexistent/directory",
 		DryRun: true,
 	}
-	err := Import(config.DefaultConfig(), opts)
+	err := Import(testImportConfig(), opts)
 	if err == nil {
 		t.Error("Import() should return error for non-existent directory")
 	}
@@ -245,7 +257,7 @@ func TestImportSkipTaps(t *testing.T) {
 	}
 
 	// This should not error - taps should be skipped
-	err = Import(config.DefaultConfig(), opts)
+	err = Import(testImportConfig(), opts)
 	if err != nil {
 		t.Errorf("Import() with SkipTaps error = %v", err)
 	}
@@ -325,7 +337,7 @@ package3`
 	}
 
 	// This should not error - comments and empty lines should be skipped
-	err = Import(config.DefaultConfig(), opts)
+	err = Import(testImportConfig(), opts)
 	if err != nil {
 		t.Errorf("Import() with comments error = %v", err)
 	}
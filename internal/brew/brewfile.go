@@ -0,0 +1,477 @@
+package brew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const brewfileName = "Brewfile"
+
+// BrewfileEntry represents a single directive in a Brewfile
+type BrewfileEntry struct {
+	Type    string           // tap, brew, cask, or mas
+	Name    string           // first positional argument
+	Args    []string         // extra positional arguments (e.g., tap clone URL)
+	Options []BrewfileOption // Ruby-style keyword options in source order
+}
+
+// BrewfileOption represents a "key: value" option.
+// Value holds the Ruby literal as written (e.g., `true`, `:changed`, `["HEAD"]`).
+type BrewfileOption struct {
+	Key   string
+	Value string
+}
+
+var (
+	brewfileDirectivePattern = regexp.MustCompile(`^([a-z_]+)\s+(.*)$`)
+	brewfileOptionPattern    = regexp.MustCompile(`^:?([A-Za-z_][\w-]*)\s*(?::|=>)\s*(.*)$`)
+)
+
+// Option returns the raw value of an option
+func (e BrewfileEntry) Option(key string) (string, bool) {
+	for _, opt := range e.Options {
+		if opt.Key == key {
+			return opt.Value, true
+		}
+	}
+	return "", false
+}
+
+// String renders the entry as a Brewfile line
+func (e BrewfileEntry) String() string {
+	parts := []string{fmt.Sprintf("%s %q", e.Type, e.Name)}
+	for _, arg := range e.Args {
+		parts = append(parts, fmt.Sprintf("%q", arg))
+	}
+	for _, opt := range e.Options {
+		parts = append(parts, fmt.Sprintf("%s: %s", opt.Key, opt.Value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseBrewfile parses Brewfile content into entries.
+// Lines that are not directives with a quoted name (e.g., "if OS.mac?",
+// "cask_args ...") are ignored.
+func ParseBrewfile(content string) ([]BrewfileEntry, error) {
+	var entries []BrewfileEntry
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(stripRubyComment(lines[i]))
+
+		// Options may span multiple lines while brackets are open
+		for rubyDepth(line) > 0 && i+1 < len(lines) {
+			i++
+			line += " " + strings.TrimSpace(stripRubyComment(lines[i]))
+		}
+
+		if line == "" {
+			continue
+		}
+
+		m := brewfileDirectivePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		parts, err := splitRubyArgs(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if len(parts) == 0 || !isRubyString(parts[0]) {
+			continue
+		}
+
+		entry := BrewfileEntry{
+			Type: m[1],
+			Name: unquoteRuby(parts[0]),
+		}
+		for _, part := range parts[1:] {
+			if isRubyString(part) {
+				entry.Args = append(entry.Args, unquoteRuby(part))
+				continue
+			}
+			om := brewfileOptionPattern.FindStringSubmatch(part)
+			if om == nil {
+				return nil, fmt.Errorf("line %d: unexpected argument %q", lineNum, part)
+			}
+			entry.Options = append(entry.Options, BrewfileOption{Key: om[1], Value: strings.TrimSpace(om[2])})
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GenerateBrewfile renders entries as Brewfile content
+func GenerateBrewfile(entries []BrewfileEntry) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by goodbye export brew\n")
+
+	for _, entryType := range []string{"tap", "brew", "cask", "mas"} {
+		for _, e := range entries {
+			if e.Type == entryType {
+				sb.WriteString(e.String())
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// exportBrewfile exports the current Homebrew environment as a Brewfile
+func exportBrewfile(cfg *config.Config, opts ExportOptions) error {
+	entries, err := collectBrewfileEntries(cfg, opts.Verbose)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(opts.Dir, brewfileName)
+	content := GenerateBrewfile(entries)
+
+	if opts.DryRun {
+		fmt.Println("[dry-run] Would create directory:", opts.Dir)
+		fmt.Printf("[dry-run] Would create file: %s\n", filePath)
+		fmt.Println("[dry-run] Content preview:")
+		for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", opts.Dir, err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	fmt.Printf("Exported %d entries to %s\n", len(entries), filePath)
+
+	fmt.Println("\nExport completed successfully!")
+	return nil
+}
+
+// collectBrewfileEntries gathers taps, formulas, casks and Mac App Store apps.
+// Link state, service state and mas apps are optional and skipped when
+// their commands fail.
+func collectBrewfileEntries(cfg *config.Config, verbose bool) ([]BrewfileEntry, error) {
+	var entries []BrewfileEntry
+
+	taps, err := runCommand(cfg.Brew.Export.TapCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get taps: %w", err)
+	}
+	remotes := exportTapRemotes(cfg, verbose)
+	for _, tap := range taps {
+		entry := BrewfileEntry{Type: "tap", Name: tap}
+		if remote, ok := remotes[tap]; ok {
+			entry.Args = []string{remote}
+		}
+		entries = append(entries, entry)
+	}
+
+	formulas, err := runCommand(cfg.Brew.Export.FormulaCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get formulas: %w", err)
+	}
+
+	var info *Info
+	if cfg.Brew.Export.InfoCmd != "" {
+		info, err = getInfo(cfg.Brew.Export.InfoCmd)
+		if err != nil && verbose {
+			fmt.Printf("Warning: failed to get formula info: %v\n", err)
+		}
+	}
+	infoByName := formulaInfoByName(info)
+
+	running := make(map[string]bool)
+	if cfg.Brew.Export.ServicesCmd != "" {
+		services, err := getServices(cfg.Brew.Export.ServicesCmd)
+		if err != nil && verbose {
+			fmt.Printf("Warning: failed to get services: %v\n", err)
+		}
+		for _, svc := range services {
+			if svc.Running() {
+				running[svc.Name] = true
+			}
+		}
+	}
+
+	qualified := qualifyNames(formulas, info, false)
+	for i, formula := range formulas {
+		entry := BrewfileEntry{Type: "brew", Name: qualified[i]}
+		if f, ok := infoByName[formula]; ok {
+			if f.KegOnly && f.LinkedKeg != "" {
+				entry.Options = append(entry.Options, BrewfileOption{Key: "link", Value: "true"})
+			} else if !f.KegOnly && f.LinkedKeg == "" {
+				entry.Options = append(entry.Options, BrewfileOption{Key: "link", Value: "false"})
+			}
+		}
+		if running[formula] {
+			entry.Options = append(entry.Options, BrewfileOption{Key: "restart_service", Value: "true"})
+		}
+		entries = append(entries, entry)
+	}
+
+	casks, err := runCommand(cfg.Brew.Export.CaskCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get casks: %w", err)
+	}
+	for _, cask := range qualifyNames(casks, info, true) {
+		entries = append(entries, BrewfileEntry{Type: "cask", Name: cask})
+	}
+
+	if cfg.Brew.Export.MasCmd != "" {
+		lines, err := runCommand(cfg.Brew.Export.MasCmd)
+		if err != nil && verbose {
+			fmt.Printf("Warning: failed to get Mac App Store apps: %v\n", err)
+		}
+		for _, app := range parseMasList(lines) {
+			entries = append(entries, BrewfileEntry{
+				Type:    "mas",
+				Name:    app.Name,
+				Options: []BrewfileOption{{Key: "id", Value: app.ID}},
+			})
+		}
+	}
+
+	return entries, nil
+}
+
+// readBrewfileGroups reads a Brewfile and converts its entries into import groups
+func readBrewfileGroups(cfg *config.Config, dir string) (taps, formulas, casks, masApps importGroup, err error) {
+	filePath := filepath.Join(dir, brewfileName)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return taps, formulas, casks, masApps, fmt.Errorf("failed to read %s: %w", brewfileName, err)
+	}
+
+	entries, err := ParseBrewfile(string(content))
+	if err != nil {
+		return taps, formulas, casks, masApps, fmt.Errorf("failed to parse %s: %w", brewfileName, err)
+	}
+
+	taps = importGroup{Label: brewfileName + " (tap)", Type: "tap", Cmd: tapInstallCmd(cfg), Serial: true}
+	formulas = importGroup{Label: brewfileName + " (brew)", Type: "formula", Cmd: formulaInstallCmd(cfg), Batchable: true}
+	casks = importGroup{Label: brewfileName + " (cask)", Type: "cask", Cmd: caskInstallCmd(cfg), Batchable: true}
+	masApps = importGroup{Label: brewfileName + " (mas)", Type: "mas", Cmd: masInstallCmd(cfg), Batchable: true}
+
+	for _, e := range entries {
+		switch e.Type {
+		case "tap":
+			// tap "user/repo", "https://..." -> brew tap user/repo https://...
+			taps.Items = append(taps.Items, importItem{Name: e.Name, TrailingArgs: e.Args})
+		case "brew":
+			formulas.Items = append(formulas.Items, importItem{
+				Name:     e.Name,
+				Args:     brewfileInstallArgs(e),
+				PostCmds: brewfilePostCmds(cfg, e),
+			})
+		case "cask":
+			casks.Items = append(casks.Items, importItem{
+				Name: e.Name,
+				Args: brewfileInstallArgs(e),
+			})
+		case "mas":
+			id, ok := e.Option("id")
+			if !ok {
+				continue
+			}
+			masApps.Items = append(masApps.Items, importItem{Name: unquoteRuby(id), Comment: e.Name})
+		}
+	}
+
+	return taps, formulas, casks, masApps, nil
+}
+
+// brewfilePostCmds returns the link and service commands implied by a brew entry
+func brewfilePostCmds(cfg *config.Config, e BrewfileEntry) []string {
+	var cmds []string
+
+	if link, ok := e.Option("link"); ok {
+		if rubyTruthy(link) {
+			linkCmd := cfg.Brew.Import.LinkCmd
+			if linkCmd == "" {
+				linkCmd = "brew link --force"
+			}
+			cmds = append(cmds, fmt.Sprintf("%s %s", linkCmd, e.Name))
+		} else {
+			unlinkCmd := cfg.Brew.Import.UnlinkCmd
+			if unlinkCmd == "" {
+				unlinkCmd = "brew unlink"
+			}
+			cmds = append(cmds, fmt.Sprintf("%s %s", unlinkCmd, e.Name))
+		}
+	}
+
+	if restart, ok := e.Option("restart_service"); ok && rubyTruthy(restart) {
+		restartCmd := cfg.Brew.Import.ServiceRestartCmd
+		if restartCmd == "" {
+			restartCmd = "brew services restart"
+		}
+		cmds = append(cmds, fmt.Sprintf("%s %s", restartCmd, e.Name))
+	}
+
+	return cmds
+}
+
+// brewfileInstallArgs converts the "args" option into command-line flags
+// (e.g., args: ["HEAD"] -> --HEAD, args: { appdir: "~/Apps" } -> --appdir=~/Apps)
+func brewfileInstallArgs(e BrewfileEntry) []string {
+	raw, ok := e.Option("args")
+	if !ok {
+		return nil
+	}
+
+	var flags []string
+	raw = strings.TrimSpace(raw)
+	switch {
+	case strings.HasPrefix(raw, "["):
+		parts, _ := splitRubyArgs(raw[1 : len(raw)-1])
+		for _, part := range parts {
+			flags = append(flags, "--"+unquoteRuby(part))
+		}
+	case strings.HasPrefix(raw, "{"):
+		parts, _ := splitRubyArgs(raw[1 : len(raw)-1])
+		for _, part := range parts {
+			key, value := part, "true"
+			if om := brewfileOptionPattern.FindStringSubmatch(part); om != nil {
+				key, value = om[1], strings.TrimSpace(om[2])
+			} else if kv := strings.SplitN(part, "=>", 2); len(kv) == 2 {
+				key, value = unquoteRuby(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1])
+			}
+			key = unquoteRuby(key)
+			switch value {
+			case "true":
+				flags = append(flags, "--"+key)
+			case "false", "nil":
+			default:
+				flags = append(flags, fmt.Sprintf("--%s=%s", key, unquoteRuby(value)))
+			}
+		}
+	}
+	return flags
+}
+
+// rubyTruthy reports whether an option value enables a feature
+// (`true` and symbols such as `:changed` do; `false` and `nil` do not)
+func rubyTruthy(value string) bool {
+	return value != "" && value != "false" && value != "nil"
+}
+
+// splitRubyArgs splits a Ruby argument list on top-level commas
+func splitRubyArgs(s string) ([]string, error) {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			current.WriteByte(c)
+			if c == '\\' && i+1 < len(s) {
+				i++
+				current.WriteByte(s[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced %q", c)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(current.String()))
+				current.Reset()
+				continue
+			}
+		}
+		current.WriteByte(c)
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string")
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced brackets")
+	}
+	if last := strings.TrimSpace(current.String()); last != "" {
+		parts = append(parts, last)
+	}
+	return parts, nil
+}
+
+// stripRubyComment removes a trailing # comment outside of string literals
+func stripRubyComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// rubyDepth returns the number of unclosed brackets in a line
+func rubyDepth(line string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+		}
+	}
+	return depth
+}
+
+func isRubyString(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]
+}
+
+func unquoteRuby(s string) string {
+	s = strings.TrimSpace(s)
+	if isRubyString(s) {
+		s = s[1 : len(s)-1]
+		s = strings.ReplaceAll(s, `\"`, `"`)
+		return strings.ReplaceAll(s, `\'`, `'`)
+	}
+	return strings.TrimPrefix(s, ":")
+}
@@ -0,0 +1,317 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestParseBrewfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []BrewfileEntry
+		wantErr  bool
+	}{
+		{
+			name:     "empty content",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name: "simple directives",
+			input: `tap "homebrew/bundle"
+brew "jq"
+cask "firefox"`,
+			expected: []BrewfileEntry{
+				{Type: "tap", Name: "homebrew/bundle"},
+				{Type: "brew", Name: "jq"},
+				{Type: "cask", Name: "firefox"},
+			},
+		},
+		{
+			name: "comments and blank lines",
+			input: `# Taps
+tap "hashicorp/tap" # official tap
+
+# Formulas
+brew "hashicorp/tap/terraform"`,
+			expected: []BrewfileEntry{
+				{Type: "tap", Name: "hashicorp/tap"},
+				{Type: "brew", Name: "hashicorp/tap/terraform"},
+			},
+		},
+		{
+			name:  "ruby-style options",
+			input: `brew "postgresql@16", restart_service: :changed, link: true`,
+			expected: []BrewfileEntry{
+				{Type: "brew", Name: "postgresql@16", Options: []BrewfileOption{
+					{Key: "restart_service", Value: ":changed"},
+					{Key: "link", Value: "true"},
+				}},
+			},
+		},
+		{
+			name:  "tap with clone URL",
+			input: `tap "user/private", "https://example.com/user/homebrew-private.git"`,
+			expected: []BrewfileEntry{
+				{Type: "tap", Name: "user/private", Args: []string{"https://example.com/user/homebrew-private.git"}},
+			},
+		},
+		{
+			name:  "mas with id",
+			input: `mas "Xcode", id: 497799835`,
+			expected: []BrewfileEntry{
+				{Type: "mas", Name: "Xcode", Options: []BrewfileOption{{Key: "id", Value: "497799835"}}},
+			},
+		},
+		{
+			name: "multi-line options",
+			input: `cask "firefox", args: {
+  appdir: "~/Applications", # per-user
+}`,
+			expected: []BrewfileEntry{
+				{Type: "cask", Name: "firefox", Options: []BrewfileOption{
+					{Key: "args", Value: `{ appdir: "~/Applications", }`},
+				}},
+			},
+		},
+		{
+			name:  "hash in string is not a comment",
+			input: `tap "user/repo", "https://example.com/repo#main"`,
+			expected: []BrewfileEntry{
+				{Type: "tap", Name: "user/repo", Args: []string{"https://example.com/repo#main"}},
+			},
+		},
+		{
+			name: "non-directive ruby lines are ignored",
+			input: `cask_args appdir: "/Applications"
+if OS.mac?
+  brew "mas"
+end`,
+			expected: []BrewfileEntry{
+				{Type: "brew", Name: "mas"},
+			},
+		},
+		{
+			name:    "unterminated string",
+			input:   `brew "jq`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseBrewfile(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBrewfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseBrewfile() = %#v, want %#v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateBrewfileRoundTrip(t *testing.T) {
+	entries := []BrewfileEntry{
+		{Type: "cask", Name: "firefox"},
+		{Type: "brew", Name: "postgresql@16", Options: []BrewfileOption{
+			{Key: "restart_service", Value: "true"},
+			{Key: "link", Value: "true"},
+		}},
+		{Type: "mas", Name: "Xcode", Options: []BrewfileOption{{Key: "id", Value: "497799835"}}},
+		{Type: "tap", Name: "hashicorp/tap"},
+	}
+
+	content := GenerateBrewfile(entries)
+	expected := `# Generated by goodbye export brew
+tap "hashicorp/tap"
+brew "postgresql@16", restart_service: true, link: true
+cask "firefox"
+mas "Xcode", id: 497799835
+`
+	if content != expected {
+		t.Errorf("GenerateBrewfile() = %q, want %q", content, expected)
+	}
+
+	parsed, err := ParseBrewfile(content)
+	if err != nil {
+		t.Fatalf("ParseBrewfile() error = %v", err)
+	}
+	if len(parsed) != len(entries) {
+		t.Fatalf("round trip produced %d entries, want %d", len(parsed), len(entries))
+	}
+	if parsed[1].String() != `brew "postgresql@16", restart_service: true, link: true` {
+		t.Errorf("round trip entry = %q", parsed[1].String())
+	}
+}
+
+func TestBrewfileInstallArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []BrewfileOption
+		expected []string
+	}{
+		{
+			name:     "no args",
+			expected: nil,
+		},
+		{
+			name:     "array args",
+			options:  []BrewfileOption{{Key: "args", Value: `["HEAD", "with-debug"]`}},
+			expected: []string{"--HEAD", "--with-debug"},
+		},
+		{
+			name:     "hash args",
+			options:  []BrewfileOption{{Key: "args", Value: `{ appdir: "~/Applications", "no-quarantine" => true, require_sha: false }`}},
+			expected: []string{"--appdir=~/Applications", "--no-quarantine"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := brewfileInstallArgs(BrewfileEntry{Type: "brew", Name: "x", Options: tt.options})
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("brewfileInstallArgs() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBrewfilePostCmds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	entry := BrewfileEntry{Type: "brew", Name: "postgresql@16", Options: []BrewfileOption{
+		{Key: "link", Value: "false"},
+		{Key: "restart_service", Value: ":changed"},
+	}}
+
+	expected := []string{
+		"brew unlink postgresql@16",
+		"brew services restart postgresql@16",
+	}
+	if result := brewfilePostCmds(cfg, entry); !reflect.DeepEqual(result, expected) {
+		t.Errorf("brewfilePostCmds() = %v, want %v", result, expected)
+	}
+}
+
+func TestReadBrewfileGroups(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `tap "user/private", "https://example.com/private.git"
+brew "jq"
+brew "vim", args: ["HEAD"]
+cask "firefox"
+mas "Xcode", id: 497799835
+vscode "golang.go"`
+	if err := os.WriteFile(filepath.Join(tmpDir, "Brewfile"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write Brewfile: %v", err)
+	}
+
+	taps, formulas, casks, masApps, err := readBrewfileGroups(config.DefaultConfig(), tmpDir)
+	if err != nil {
+		t.Fatalf("readBrewfileGroups() error = %v", err)
+	}
+
+	if got := taps.Items[0].command(taps.Cmd); got != "brew tap user/private https://example.com/private.git" {
+		t.Errorf("tap command = %q", got)
+	}
+	if len(formulas.Items) != 2 {
+		t.Fatalf("formulas = %d, want 2", len(formulas.Items))
+	}
+	if got := formulas.Items[1].command(formulas.Cmd); got != "brew install --HEAD vim" {
+		t.Errorf("formula command = %q", got)
+	}
+	if got := casks.Items[0].command(casks.Cmd); got != "brew install --cask firefox" {
+		t.Errorf("cask command = %q", got)
+	}
+	if got := masApps.Items[0].command(masApps.Cmd); got != "mas install 497799835" {
+		t.Errorf("mas command = %q", got)
+	}
+}
+
+func TestImportBrewfileDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "Brewfile"), []byte("brew \"jq\"\ncask \"firefox\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write Brewfile: %v", err)
+	}
+
+	for _, only := range []string{"", "formula", "cask", "tap"} {
+		opts := ImportOptions{Dir: tmpDir, DryRun: true, Only: only}
+		if err := Import(testImportConfig(), opts); err != nil {
+			t.Errorf("Import() with Brewfile and only=%q error = %v", only, err)
+		}
+	}
+}
+
+func TestResolveImportFormat(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	tests := []struct {
+		name     string
+		files    []string
+		format   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "txt files", files: []string{"formula.txt", "Brewfile"}, expected: "txt"},
+		{name: "only Brewfile", files: []string{"Brewfile"}, expected: "brewfile"},
+		{name: "no files", expected: "txt"},
+		{name: "explicit brewfile", files: []string{"formula.txt"}, format: "brewfile", expected: "brewfile"},
+		{name: "invalid format", format: "yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, f), []byte{}, 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", f, err)
+				}
+			}
+			result, err := resolveImportFormat(cfg, ImportOptions{Dir: tmpDir, Format: tt.format})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveImportFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("resolveImportFormat() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportBrewfileWithFakeCommands(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.TapCmd = "echo hashicorp/tap"
+	cfg.Brew.Export.FormulaCmd = "printf 'jq\\npostgresql@16\\n'"
+	cfg.Brew.Export.CaskCmd = "echo firefox"
+	cfg.Brew.Export.InfoCmd = `echo '{"formulae":[{"name":"postgresql@16","keg_only":true,"linked_keg":"16.1"},{"name":"jq","linked_keg":"1.7"}]}'`
+	cfg.Brew.Export.ServicesCmd = `echo '[{"name":"postgresql@16","status":"started","user":"me"}]'`
+	cfg.Brew.Export.MasCmd = "echo '497799835  Xcode  (15.2)'"
+	cfg.Brew.Export.TapInfoCmd = ""
+
+	opts := ExportOptions{Dir: tmpDir, Format: "brewfile"}
+	if err := Export(cfg, opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "Brewfile"))
+	if err != nil {
+		t.Fatalf("failed to read Brewfile: %v", err)
+	}
+	for _, want := range []string{
+		`tap "hashicorp/tap"`,
+		`brew "jq"` + "\n",
+		`brew "postgresql@16", link: true, restart_service: true`,
+		`cask "firefox"`,
+		`mas "Xcode", id: 497799835`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Brewfile missing %q:\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package brew
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// Compatibility statuses reported by Compat
+const (
+	CompatBottle      = "bottle"      // a bottle is available for the target
+	CompatSource      = "source"      // no bottle for the target; brew builds from source
+	CompatRosetta     = "rosetta"     // Intel-only cask that needs Rosetta 2 on Apple Silicon
+	CompatUnsupported = "unsupported" // cannot be installed on the target
+	CompatMacOSOnly   = "macos-only"  // cask or formula that requires macOS on a Linux target
+	CompatUnknown     = "unknown"     // brew info does not know the package
+)
+
+// CompatOptions represents options for the compat command
+type CompatOptions struct {
+	Dir     string
+	Target  string // "<os>/<arch>" (e.g., darwin/arm64, linux/x86_64); "" for this machine
+	Format  string // "txt", "brewfile", or "" to auto-detect
+	Verbose bool
+	JSON    bool
+}
+
+// CompatTarget represents the OS and architecture packages are migrated to
+type CompatTarget struct {
+	OS   string `json:"os"`   // darwin or linux
+	Arch string `json:"arch"` // arm64 or x86_64
+}
+
+func (t CompatTarget) String() string {
+	return t.OS + "/" + t.Arch
+}
+
+// CompatEntry represents the compatibility of a single exported package
+type CompatEntry struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"` // formula or cask
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+// CompatReport represents the compatibility of an export with a target
+type CompatReport struct {
+	Target  CompatTarget  `json:"target"`
+	Entries []CompatEntry `json:"entries"`
+}
+
+// Issues returns the entries that do not install from a bottle
+func (r *CompatReport) Issues() []CompatEntry {
+	var issues []CompatEntry
+	for _, e := range r.Entries {
+		if e.Status != CompatBottle {
+			issues = append(issues, e)
+		}
+	}
+	return issues
+}
+
+// Compat reports whether the exported formulas and casks can be installed
+// from bottles on the target architecture and OS
+func Compat(cfg *config.Config, opts CompatOptions, w io.Writer) error {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+
+	// Expand ~ to home directory
+	if strings.HasPrefix(opts.Dir, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.Dir = filepath.Join(homeDir, opts.Dir[1:])
+	}
+
+	target, err := parseCompatTarget(opts.Target)
+	if err != nil {
+		return err
+	}
+
+	_, formulas, casks, err := readExportGroups(cfg, opts.Dir, opts.Format)
+	if err != nil {
+		return err
+	}
+
+	infoCmd := cfg.Brew.Import.InfoCmd
+	if infoCmd == "" {
+		infoCmd = "brew info --json=v2"
+	}
+	info := &Info{}
+	if names := itemNames(formulas); len(names) > 0 {
+		info.Formulae = lookupInfo(infoCmd, names).Formulae
+	}
+	// Casks are reported as macOS-only on Linux without looking them up
+	if names := itemNames(casks); len(names) > 0 && target.OS == "darwin" {
+		info.Casks = lookupInfo(infoCmd+" --cask", names).Casks
+	}
+
+	report := buildCompatReport(target, itemNames(formulas), itemNames(casks), info)
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	printCompatReport(w, report, opts.Verbose)
+	return nil
+}
+
+// itemNames returns the item names of a group as exported
+func itemNames(group importGroup) []string {
+	names := make([]string, 0, len(group.Items))
+	for _, item := range group.Items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// parseCompatTarget parses "<os>/<arch>", defaulting to this machine
+func parseCompatTarget(s string) (CompatTarget, error) {
+	if s == "" {
+		s = runtime.GOOS + "/" + runtime.GOARCH
+	}
+	osName, arch, ok := strings.Cut(strings.ToLower(s), "/")
+	if !ok {
+		return CompatTarget{}, fmt.Errorf("invalid target %q (expected <os>/<arch>, e.g., darwin/arm64)", s)
+	}
+
+	var target CompatTarget
+	switch osName {
+	case "darwin", "macos":
+		target.OS = "darwin"
+	case "linux":
+		target.OS = "linux"
+	default:
+		return CompatTarget{}, fmt.Errorf("unsupported target OS %q (must be darwin or linux)", osName)
+	}
+	switch arch {
+	case "arm64", "aarch64":
+		target.Arch = "arm64"
+	case "x86_64", "amd64", "intel":
+		target.Arch = "x86_64"
+	default:
+		return CompatTarget{}, fmt.Errorf("unsupported target architecture %q (must be arm64 or x86_64)", arch)
+	}
+	return target, nil
+}
+
+// matchesBottleTag reports whether a bottle tag can be poured on the target.
+// macOS tags are arm64_<release> for Apple Silicon and a bare <release>
+// (e.g., sonoma, big_sur) for Intel; Linux tags are <arch>_linux.
+func (t CompatTarget) matchesBottleTag(tag string) bool {
+	if tag == "all" {
+		return true
+	}
+	if t.OS == "linux" {
+		return tag == t.Arch+"_linux"
+	}
+	if strings.HasSuffix(tag, "_linux") {
+		return false
+	}
+	return strings.HasPrefix(tag, "arm64_") == (t.Arch == "arm64")
+}
+
+// buildCompatReport classifies each formula and cask for the target
+func buildCompatReport(target CompatTarget, formulas, casks []string, info *Info) *CompatReport {
+	report := &CompatReport{Target: target}
+
+	byName := make(map[string]FormulaInfo)
+	for _, f := range info.Formulae {
+		for _, known := range f.knownNames() {
+			if known != "" {
+				byName[known] = f
+			}
+		}
+	}
+	for _, name := range formulas {
+		f, ok := byName[name]
+		if !ok {
+			f, ok = byName[bareName(name)]
+		}
+		entry := CompatEntry{Name: name, Type: "formula"}
+		if ok {
+			entry.Status, entry.Note = formulaCompat(target, f)
+		} else {
+			entry.Status, entry.Note = CompatUnknown, "not found by brew info"
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+
+	byToken := make(map[string]CaskInfo)
+	for _, c := range info.Casks {
+		byToken[c.Token] = c
+		if c.FullToken != "" {
+			byToken[c.FullToken] = c
+		}
+	}
+	for _, name := range casks {
+		c, ok := byToken[name]
+		if !ok {
+			c, ok = byToken[bareName(name)]
+		}
+		entry := CompatEntry{Name: name, Type: "cask"}
+		switch {
+		case target.OS == "linux":
+			// Casks are macOS applications
+			entry.Status, entry.Note = CompatMacOSOnly, "casks are not supported on Linux"
+		case ok:
+			entry.Status, entry.Note = caskCompat(target, c)
+		default:
+			entry.Status, entry.Note = CompatUnknown, "not found by brew info"
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+
+	return report
+}
+
+// formulaCompat classifies a formula by its requirements and bottle tags
+func formulaCompat(target CompatTarget, f FormulaInfo) (string, string) {
+	for _, r := range f.Requirements {
+		switch {
+		case r.Name == "macos" && target.OS == "linux":
+			return CompatMacOSOnly, "requires macOS"
+		case r.Name == "linux" && target.OS == "darwin":
+			return CompatUnsupported, "requires Linux"
+		}
+	}
+
+	var tags []string
+	for _, tag := range bottleTags(f) {
+		if target.matchesBottleTag(tag) {
+			return CompatBottle, ""
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		return CompatSource, "no bottles"
+	}
+	return CompatSource, "no bottle for " + target.String() + " (has " + truncateList(tags, 3) + ")"
+}
+
+// bottleTags returns the stable bottle tags of a formula in sorted order
+func bottleTags(f FormulaInfo) []string {
+	files := f.Bottle["stable"].Files
+	tags := make([]string, 0, len(files))
+	for tag := range files {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// caskArchRequirement represents depends_on.arch of a cask. brew encodes
+// arch: :arm64 as {"type": "arm", "bits": 64} and :intel as
+// {"type": "intel", "bits": 64}.
+type caskArchRequirement struct {
+	Type string `json:"type"` // intel or arm
+	Bits int    `json:"bits"`
+}
+
+// caskArches returns the architectures (arm64 or x86_64) a cask is
+// restricted to, or nil if it has no architecture requirement (or it
+// cannot be parsed)
+func caskArches(c CaskInfo) []string {
+	if len(c.DependsOn) == 0 {
+		return nil
+	}
+	var dependsOn struct {
+		Arch []caskArchRequirement `json:"arch"`
+	}
+	if err := json.Unmarshal(c.DependsOn, &dependsOn); err != nil {
+		return nil
+	}
+	var arches []string
+	for _, a := range dependsOn.Arch {
+		switch a.Type {
+		case "arm", "arm64":
+			arches = append(arches, "arm64")
+		case "intel", "x86_64":
+			arches = append(arches, "x86_64")
+		}
+	}
+	return arches
+}
+
+// caskCompat classifies a cask on a macOS target by its architecture requirement
+func caskCompat(target CompatTarget, c CaskInfo) (string, string) {
+	arches := caskArches(c)
+	if len(arches) == 0 {
+		return CompatBottle, ""
+	}
+	for _, arch := range arches {
+		if arch == target.Arch {
+			return CompatBottle, ""
+		}
+	}
+	if target.Arch == "arm64" {
+		return CompatRosetta, "Intel-only; requires Rosetta 2"
+	}
+	return CompatUnsupported, "Apple Silicon only"
+}
+
+func printCompatReport(w io.Writer, report *CompatReport, verbose bool) {
+	fmt.Fprintf(w, "Compatibility with %s\n\n", report.Target)
+
+	entries := report.Issues()
+	if verbose {
+		entries = report.Entries
+	}
+	if len(entries) > 0 {
+		fmt.Fprintf(w, "%-28s %-8s %-12s %s\n", "NAME", "TYPE", "STATUS", "NOTE")
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		for _, e := range entries {
+			fmt.Fprintf(w, "%-28s %-8s %-12s %s\n", e.Name, e.Type, e.Status, e.Note)
+		}
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+	}
+
+	counts := make(map[string]int)
+	for _, e := range report.Entries {
+		counts[e.Status]++
+	}
+	fmt.Fprintf(w, "\n%d packages: %d bottle, %d source, %d rosetta, %d macos-only, %d unsupported, %d unknown\n",
+		len(report.Entries), counts[CompatBottle], counts[CompatSource], counts[CompatRosetta],
+		counts[CompatMacOSOnly], counts[CompatUnsupported], counts[CompatUnknown])
+	if len(report.Issues()) == 0 {
+		fmt.Fprintln(w, "All packages can be installed from bottles.")
+	}
+}
@@ -0,0 +1,213 @@
+package brew
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const sampleCompatInfo = `{
+  "formulae": [
+    {"name": "jq", "full_name": "jq", "tap": "homebrew/core",
+     "bottle": {"stable": {"files": {"arm64_sonoma": {}, "sonoma": {}, "x86_64_linux": {}}}}},
+    {"name": "legacy", "full_name": "legacy", "tap": "homebrew/core",
+     "bottle": {"stable": {"files": {"ventura": {}, "x86_64_linux": {}}}}},
+    {"name": "fonts-tool", "full_name": "fonts-tool", "tap": "homebrew/core",
+     "bottle": {"stable": {"files": {"all": {}}}}},
+    {"name": "pinentry-mac", "full_name": "pinentry-mac", "tap": "homebrew/core",
+     "requirements": [{"name": "macos"}],
+     "bottle": {"stable": {"files": {"arm64_sonoma": {}, "sonoma": {}}}}},
+    {"name": "mytool", "full_name": "me/tap/mytool", "tap": "me/tap", "bottle": {}}
+  ],
+  "casks": [
+    {"token": "firefox", "full_token": "firefox", "tap": "homebrew/cask", "depends_on": {"macos": {">=": ["10.15"]}}},
+    {"token": "old-app", "full_token": "old-app", "tap": "homebrew/cask", "depends_on": {"arch": [{"type": "intel", "bits": 64}]}},
+    {"token": "new-app", "full_token": "new-app", "tap": "homebrew/cask", "depends_on": {"arch": [{"type": "arm", "bits": 64}]}}
+  ]
+}`
+
+func TestCaskArches(t *testing.T) {
+	tests := []struct {
+		dependsOn string
+		expected  []string
+	}{
+		{`{"arch": [{"type": "arm", "bits": 64}]}`, []string{"arm64"}},
+		{`{"arch": [{"type": "intel", "bits": 64}]}`, []string{"x86_64"}},
+		{`{"arch": [{"type": "intel", "bits": 64}, {"type": "arm", "bits": 64}]}`, []string{"x86_64", "arm64"}},
+		{`{"macos": {">=": ["12"]}}`, nil},
+		{``, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dependsOn, func(t *testing.T) {
+			result := caskArches(CaskInfo{DependsOn: json.RawMessage(tt.dependsOn)})
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("caskArches() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseCompatTarget(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected CompatTarget
+		wantErr  bool
+	}{
+		{"darwin/arm64", CompatTarget{OS: "darwin", Arch: "arm64"}, false},
+		{"macos/intel", CompatTarget{OS: "darwin", Arch: "x86_64"}, false},
+		{"linux/amd64", CompatTarget{OS: "linux", Arch: "x86_64"}, false},
+		{"Linux/aarch64", CompatTarget{OS: "linux", Arch: "arm64"}, false},
+		{"arm64", CompatTarget{}, true},
+		{"windows/amd64", CompatTarget{}, true},
+		{"darwin/ppc", CompatTarget{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseCompatTarget(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCompatTarget(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("parseCompatTarget(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchesBottleTag(t *testing.T) {
+	arm := CompatTarget{OS: "darwin", Arch: "arm64"}
+	intel := CompatTarget{OS: "darwin", Arch: "x86_64"}
+	linux := CompatTarget{OS: "linux", Arch: "x86_64"}
+
+	tests := []struct {
+		target   CompatTarget
+		tag      string
+		expected bool
+	}{
+		{arm, "arm64_sonoma", true},
+		{arm, "sonoma", false},
+		{arm, "arm64_linux", false},
+		{intel, "big_sur", true},
+		{intel, "arm64_big_sur", false},
+		{intel, "x86_64_linux", false},
+		{linux, "x86_64_linux", true},
+		{linux, "arm64_linux", false},
+		{linux, "sonoma", false},
+		{linux, "all", true},
+	}
+
+	for _, tt := range tests {
+		if result := tt.target.matchesBottleTag(tt.tag); result != tt.expected {
+			t.Errorf("%s matchesBottleTag(%q) = %v, want %v", tt.target, tt.tag, result, tt.expected)
+		}
+	}
+}
+
+func TestBuildCompatReport(t *testing.T) {
+	info, err := parseInfo([]byte(sampleCompatInfo))
+	if err != nil {
+		t.Fatalf("parseInfo() error = %v", err)
+	}
+	formulas := []string{"jq", "legacy", "fonts-tool", "pinentry-mac", "me/tap/mytool", "missing"}
+	casks := []string{"firefox", "old-app", "new-app"}
+
+	statuses := func(report *CompatReport) map[string]string {
+		result := make(map[string]string)
+		for _, e := range report.Entries {
+			result[e.Name] = e.Status
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		target   CompatTarget
+		expected map[string]string
+	}{
+		{
+			name:   "Apple Silicon",
+			target: CompatTarget{OS: "darwin", Arch: "arm64"},
+			expected: map[string]string{
+				"jq": CompatBottle, "legacy": CompatSource, "fonts-tool": CompatBottle,
+				"pinentry-mac": CompatBottle, "me/tap/mytool": CompatSource, "missing": CompatUnknown,
+				"firefox": CompatBottle, "old-app": CompatRosetta, "new-app": CompatBottle,
+			},
+		},
+		{
+			name:   "Intel Mac",
+			target: CompatTarget{OS: "darwin", Arch: "x86_64"},
+			expected: map[string]string{
+				"jq": CompatBottle, "legacy": CompatBottle, "fonts-tool": CompatBottle,
+				"pinentry-mac": CompatBottle, "me/tap/mytool": CompatSource, "missing": CompatUnknown,
+				"firefox": CompatBottle, "old-app": CompatBottle, "new-app": CompatUnsupported,
+			},
+		},
+		{
+			name:   "Linux",
+			target: CompatTarget{OS: "linux", Arch: "x86_64"},
+			expected: map[string]string{
+				"jq": CompatBottle, "legacy": CompatBottle, "fonts-tool": CompatBottle,
+				"pinentry-mac": CompatMacOSOnly, "me/tap/mytool": CompatSource, "missing": CompatUnknown,
+				"firefox": CompatMacOSOnly, "old-app": CompatMacOSOnly, "new-app": CompatMacOSOnly,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := buildCompatReport(tt.target, formulas, casks, info)
+			if result := statuses(report); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildCompatReport() statuses = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCaskArchesIgnoresUnknownShape(t *testing.T) {
+	c := CaskInfo{Token: "odd", DependsOn: json.RawMessage(`{"arch": "intel"}`)}
+	if arches := caskArches(c); arches != nil {
+		t.Errorf("caskArches() = %v, want nil", arches)
+	}
+}
+
+func TestCompat(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"formula.txt": "jq\nlegacy\n",
+		"cask.txt":    "old-app\n",
+		"info.json":   sampleCompatInfo,
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	// Ignore the names passed by lookupInfo
+	cfg.Brew.Import.InfoCmd = `sh -c 'cat ` + filepath.Join(tmpDir, "info.json") + `' _`
+
+	var buf bytes.Buffer
+	opts := CompatOptions{Dir: tmpDir, Target: "darwin/arm64", JSON: true}
+	if err := Compat(cfg, opts, &buf); err != nil {
+		t.Fatalf("Compat() error = %v", err)
+	}
+
+	var report CompatReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	expected := []CompatEntry{
+		{Name: "legacy", Type: "formula", Status: CompatSource, Note: "no bottle for darwin/arm64 (has ventura, x86_64_linux)"},
+		{Name: "old-app", Type: "cask", Status: CompatRosetta, Note: "Intel-only; requires Rosetta 2"},
+	}
+	if issues := report.Issues(); !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Issues() = %+v, want %+v", issues, expected)
+	}
+}
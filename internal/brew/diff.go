@@ -0,0 +1,210 @@
+package brew
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// ErrDrift is returned by Diff when the live system differs from the export
+var ErrDrift = errors.New("drift detected between export and installed packages")
+
+// DiffOptions represents options for the diff command
+type DiffOptions struct {
+	Dir     string
+	Verbose bool
+	JSON    bool
+	Format  string // "txt", "brewfile", or "" to auto-detect
+}
+
+// DiffSet compares one package type between the export and the live system.
+// Added items are installed but not exported; removed items are exported but not installed.
+type DiffSet struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Common  []string `json:"common"`
+}
+
+// HasDrift reports whether the export and the live system differ
+func (s DiffSet) HasDrift() bool {
+	return len(s.Added) > 0 || len(s.Removed) > 0
+}
+
+// DiffResult represents the differences for every package type
+type DiffResult struct {
+	Formula DiffSet `json:"formula"`
+	Cask    DiffSet `json:"cask"`
+	Tap     DiffSet `json:"tap"`
+}
+
+// HasDrift reports whether any package type differs
+func (r *DiffResult) HasDrift() bool {
+	return r.Formula.HasDrift() || r.Cask.HasDrift() || r.Tap.HasDrift()
+}
+
+// Diff compares the exported files in opts.Dir with the installed packages.
+// It prints the result and returns ErrDrift when they differ.
+func Diff(cfg *config.Config, opts DiffOptions, w io.Writer) error {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+
+	// Expand ~ to home directory
+	if strings.HasPrefix(opts.Dir, "~") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.Dir = filepath.Join(homeDir, opts.Dir[1:])
+	}
+
+	if _, err := os.Stat(opts.Dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", opts.Dir)
+	}
+
+	result, err := buildDiff(cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		printDiff(w, result, opts.Verbose)
+	}
+
+	if result.HasDrift() {
+		return ErrDrift
+	}
+	return nil
+}
+
+// buildDiff reads the exported lists and the live lists and compares them
+func buildDiff(cfg *config.Config, opts DiffOptions) (*DiffResult, error) {
+	taps, formulas, casks, err := readExportGroups(cfg, opts.Dir, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	installedFormulas, err := runCommand(cfg.Brew.Export.FormulaCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get formulas: %w", err)
+	}
+	installedCasks, err := runCommand(cfg.Brew.Export.CaskCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get casks: %w", err)
+	}
+	installedTaps, err := runCommand(cfg.Brew.Export.TapCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get taps: %w", err)
+	}
+
+	return &DiffResult{
+		Formula: diffSets(groupNames(formulas), installedFormulas),
+		Cask:    diffSets(groupNames(casks), installedCasks),
+		Tap:     diffSets(groupNames(taps), installedTaps),
+	}, nil
+}
+
+// readExportGroups reads the exported tap, formula and cask lists from dir
+// in either the txt or the Brewfile format
+func readExportGroups(cfg *config.Config, dir, format string) (taps, formulas, casks importGroup, err error) {
+	importOpts := ImportOptions{Dir: dir, Format: format}
+	if format, err = resolveImportFormat(cfg, importOpts); err != nil {
+		return
+	}
+
+	if format == "brewfile" {
+		taps, formulas, casks, _, err = readBrewfileGroups(cfg, dir)
+		return
+	}
+	if taps, err = readTapGroup(cfg, dir, importOpts); err != nil {
+		return
+	}
+	if formulas, err = readImportGroup(dir, formulaFile(cfg), "", importOpts); err != nil {
+		return
+	}
+	casks, err = readImportGroup(dir, caskFile(cfg), "", importOpts)
+	return
+}
+
+// groupNames returns the item names of a group as brew list prints them,
+// so user/repo/formula entries become the bare formula name
+func groupNames(group importGroup) []string {
+	names := make([]string, 0, len(group.Items))
+	for _, item := range group.Items {
+		names = append(names, bareName(item.Name))
+	}
+	return names
+}
+
+// diffSets compares exported names with installed names
+func diffSets(exported, installed []string) DiffSet {
+	inExport := make(map[string]bool)
+	for _, name := range exported {
+		inExport[name] = true
+	}
+	inSystem := make(map[string]bool)
+	for _, name := range installed {
+		inSystem[name] = true
+	}
+
+	set := DiffSet{Added: []string{}, Removed: []string{}, Common: []string{}}
+	for name := range inSystem {
+		if inExport[name] {
+			set.Common = append(set.Common, name)
+		} else {
+			set.Added = append(set.Added, name)
+		}
+	}
+	for name := range inExport {
+		if !inSystem[name] {
+			set.Removed = append(set.Removed, name)
+		}
+	}
+	sort.Strings(set.Added)
+	sort.Strings(set.Removed)
+	sort.Strings(set.Common)
+	return set
+}
+
+func printDiff(w io.Writer, result *DiffResult, verbose bool) {
+	for _, s := range []struct {
+		label string
+		set   DiffSet
+	}{
+		{"formula", result.Formula},
+		{"cask", result.Cask},
+		{"tap", result.Tap},
+	} {
+		fmt.Fprintf(w, "%s: %d added, %d removed, %d common\n", s.label, len(s.set.Added), len(s.set.Removed), len(s.set.Common))
+		for _, name := range s.set.Added {
+			fmt.Fprintf(w, "  + %s\n", name)
+		}
+		for _, name := range s.set.Removed {
+			fmt.Fprintf(w, "  - %s\n", name)
+		}
+		if verbose {
+			for _, name := range s.set.Common {
+				fmt.Fprintf(w, "    %s\n", name)
+			}
+		}
+	}
+
+	if result.HasDrift() {
+		fmt.Fprintln(w, "\n(+ installed but not exported, - exported but not installed)")
+	} else {
+		fmt.Fprintln(w, "\nNo drift detected.")
+	}
+}
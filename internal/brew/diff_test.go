@@ -0,0 +1,107 @@
+package brew
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestDiffSets(t *testing.T) {
+	tests := []struct {
+		name      string
+		exported  []string
+		installed []string
+		expected  DiffSet
+	}{
+		{
+			name:      "no drift",
+			exported:  []string{"jq", "git"},
+			installed: []string{"git", "jq"},
+			expected:  DiffSet{Added: []string{}, Removed: []string{}, Common: []string{"git", "jq"}},
+		},
+		{
+			name:      "added and removed",
+			exported:  []string{"jq", "wget"},
+			installed: []string{"jq", "ripgrep"},
+			expected:  DiffSet{Added: []string{"ripgrep"}, Removed: []string{"wget"}, Common: []string{"jq"}},
+		},
+		{
+			name:      "empty export",
+			installed: []string{"jq"},
+			expected:  DiffSet{Added: []string{"jq"}, Removed: []string{}, Common: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := diffSets(tt.exported, tt.installed)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("diffSets() = %v, want %v", result, tt.expected)
+			}
+			if result.HasDrift() != (len(tt.expected.Added)+len(tt.expected.Removed) > 0) {
+				t.Errorf("HasDrift() = %v", result.HasDrift())
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"formula.txt": "jq\nwget\n",
+		"cask.txt":    "firefox\n",
+		"tap.txt":     "homebrew/cask-fonts\n",
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.CaskCmd = "echo firefox"
+	cfg.Brew.Export.TapCmd = "echo homebrew/cask-fonts"
+
+	t.Run("drift", func(t *testing.T) {
+		cfg.Brew.Export.FormulaCmd = "printf 'jq\\nripgrep\\n'"
+
+		var buf bytes.Buffer
+		err := Diff(cfg, DiffOptions{Dir: tmpDir, JSON: true}, &buf)
+		if !errors.Is(err, ErrDrift) {
+			t.Fatalf("Diff() error = %v, want ErrDrift", err)
+		}
+
+		var result DiffResult
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v", err)
+		}
+		if !reflect.DeepEqual(result.Formula.Added, []string{"ripgrep"}) || !reflect.DeepEqual(result.Formula.Removed, []string{"wget"}) {
+			t.Errorf("formula diff = %+v", result.Formula)
+		}
+		if result.Cask.HasDrift() || result.Tap.HasDrift() {
+			t.Errorf("cask/tap should not drift: %+v %+v", result.Cask, result.Tap)
+		}
+	})
+
+	t.Run("no drift", func(t *testing.T) {
+		cfg.Brew.Export.FormulaCmd = "printf 'jq\\nwget\\n'"
+
+		var buf bytes.Buffer
+		if err := Diff(cfg, DiffOptions{Dir: tmpDir}, &buf); err != nil {
+			t.Errorf("Diff() error = %v", err)
+		}
+	})
+}
+
+func TestDiffMissingDirectory(t *testing.T) {
+	var buf bytes.Buffer
+	err := Diff(config.DefaultConfig(), DiffOptions{Dir: filepath.Join(t.TempDir(), "missing")}, &buf)
+	if err == nil || errors.Is(err, ErrDrift) {
+		t.Errorf("Diff() error = %v, want missing directory error", err)
+	}
+}
@@ -0,0 +1,137 @@
+package brew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const logsDir = "logs"
+
+// Failure categories recorded in the journal
+const (
+	FailureAlreadyInstalled = "already installed"
+	FailureNoSuchFormula    = "no such formula"
+	FailureKegConflict      = "keg conflict"
+	FailureChecksumMismatch = "checksum mismatch"
+	FailureNetwork          = "network error"
+	FailureRequiresMacOS    = "requires macOS version"
+	FailureUnknown          = "other"
+)
+
+// failureRule maps a pattern in brew's output to a failure category
+type failureRule struct {
+	Category   string
+	Pattern    *regexp.Regexp
+	Suggestion string // %s is replaced with the item name
+}
+
+// failureRules are checked in order; the first match wins
+var failureRules = []failureRule{
+	{
+		Category:   FailureChecksumMismatch,
+		Pattern:    regexp.MustCompile(`(?i)(sha256|checksum) mismatch`),
+		Suggestion: "remove the cached download (brew cleanup --prune=all), run brew update and retry",
+	},
+	{
+		Category:   FailureNetwork,
+		Pattern:    regexp.MustCompile(`(?i)(failed to download|could not resolve host|connection (timed out|refused|reset)|failed to connect|curl: \(\d+\)|network is unreachable)`),
+		Suggestion: "check the network connection and retry with --resume",
+	},
+	{
+		Category:   FailureRequiresMacOS,
+		Pattern:    regexp.MustCompile(`(?i)(requires macos|macos .* or (newer|higher) is required|this software does not run on macos)`),
+		Suggestion: "%s needs a newer macOS; upgrade macOS or remove it from the export",
+	},
+	{
+		Category:   FailureKegConflict,
+		Pattern:    regexp.MustCompile(`(?i)(could not symlink|conflicts? with|conflicting formulae|brew link --overwrite)`),
+		Suggestion: "brew link --overwrite %s (or brew unlink the conflicting formula first)",
+	},
+	{
+		Category:   FailureNoSuchFormula,
+		Pattern:    regexp.MustCompile(`(?i)(no available (formula|cask)|no formulae or casks found|no cask with this name|is unavailable|no such (formula|cask|keg))`),
+		Suggestion: "check the name with brew search %s, or tap the repository that provides it",
+	},
+	{
+		Category:   FailureAlreadyInstalled,
+		Pattern:    regexp.MustCompile(`(?i)(is )?already installed`),
+		Suggestion: "nothing to do; use brew reinstall %s to reinstall it",
+	},
+}
+
+// classifyFailure returns the failure category for brew's output
+func classifyFailure(output string) string {
+	for _, rule := range failureRules {
+		if rule.Pattern.MatchString(output) {
+			return rule.Category
+		}
+	}
+	return FailureUnknown
+}
+
+// failureSuggestion returns a concrete next step for a failed item
+func failureSuggestion(category, name string) string {
+	for _, rule := range failureRules {
+		if rule.Category == category {
+			if strings.Contains(rule.Suggestion, "%s") {
+				return fmt.Sprintf(rule.Suggestion, name)
+			}
+			return rule.Suggestion
+		}
+	}
+	return "see the log for details"
+}
+
+// logPath returns <dir>/logs/<type>-<name>.log for an item
+func logPath(dir string, group importGroup, item importItem) string {
+	itemType := group.Type
+	if itemType == "" {
+		itemType = "item"
+	}
+	name := strings.NewReplacer("/", "_", " ", "_", string(os.PathSeparator), "_").Replace(item.Name)
+	return filepath.Join(dir, logsDir, fmt.Sprintf("%s-%s.log", itemType, name))
+}
+
+// createLog creates the log file of an item, creating the logs directory as needed
+func createLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// printFailureReport groups failed items by category with suggestions
+func printFailureReport(failed []*JournalEntry) {
+	if len(failed) == 0 {
+		return
+	}
+
+	var order []string
+	byCategory := make(map[string][]*JournalEntry)
+	for _, e := range failed {
+		category := e.Category
+		if category == "" {
+			category = FailureUnknown
+		}
+		if _, ok := byCategory[category]; !ok {
+			order = append(order, category)
+		}
+		byCategory[category] = append(byCategory[category], e)
+	}
+
+	fmt.Printf("Failed: %d\n", len(failed))
+	for _, category := range order {
+		entries := byCategory[category]
+		fmt.Printf("\n  %s (%d):\n", category, len(entries))
+		for _, e := range entries {
+			fmt.Printf("    - %s: %s (%s)\n", e.Group, e.Name, e.Error)
+			if e.Log != "" {
+				fmt.Printf("      log: %s\n", e.Log)
+			}
+			fmt.Printf("      suggestion: %s\n", failureSuggestion(category, e.Name))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"already installed", "Warning: jq 1.7.1 is already installed and up-to-date.", FailureAlreadyInstalled},
+		{"cask already installed", "Error: It seems there is already an App at '/Applications/Firefox.app'.\nError: Cask 'firefox' is already installed.", FailureAlreadyInstalled},
+		{"no such formula", "Error: No available formula with the name \"jqq\".", FailureNoSuchFormula},
+		{"no such cask", "Error: Cask 'foo' is unavailable: No Cask with this name exists.", FailureNoSuchFormula},
+		{"keg conflict", "Error: The `brew link` step did not complete successfully\nCould not symlink bin/node\nTo force the link and overwrite all conflicting files:\n  brew link --overwrite node", FailureKegConflict},
+		{"checksum mismatch", "Error: SHA256 mismatch\nExpected: abc\n  Actual: def", FailureChecksumMismatch},
+		{"network error", "curl: (6) Could not resolve host: ghcr.io\nError: Failed to download resource \"jq\"", FailureNetwork},
+		{"requires macOS", "Error: xcodes: This software does not run on macOS versions older than Sonoma.", FailureRequiresMacOS},
+		{"requires macOS cask", "Error: Cask xcodes depends on hardware architecture being one of [{:type=>:arm}] and requires macOS >= 14", FailureRequiresMacOS},
+		{"unknown", "exit status 1", FailureUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := classifyFailure(tt.output); result != tt.expected {
+				t.Errorf("classifyFailure() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFailureSuggestion(t *testing.T) {
+	if got := failureSuggestion(FailureKegConflict, "node"); !strings.HasPrefix(got, "brew link --overwrite node") {
+		t.Errorf("keg conflict suggestion = %q", got)
+	}
+	if got := failureSuggestion(FailureUnknown, "node"); got != "see the log for details" {
+		t.Errorf("unknown suggestion = %q", got)
+	}
+}
+
+func TestLogPath(t *testing.T) {
+	group := importGroup{Type: "formula"}
+	got := logPath("/tmp/export", group, importItem{Name: "hashicorp/tap/terraform"})
+	if expected := filepath.Join("/tmp/export", "logs", "formula-hashicorp_tap_terraform.log"); got != expected {
+		t.Errorf("logPath() = %q, want %q", got, expected)
+	}
+}
+
+func TestImportWritesLogsAndClassifiesFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("node\njq\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	// node fails with a link conflict, jq succeeds
+	cfg := testImportConfig()
+	cfg.Brew.Import.FormulaInstallCmd = `sh -c 'echo "installing $0"; test "$0" != node || { echo "Could not symlink bin/node" >&2; exit 1; }'`
+
+	if err := Import(cfg, ImportOptions{Dir: tmpDir, Only: "formula", Continue: true}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "logs", "formula-node.log"))
+	if err != nil {
+		t.Fatalf("failed to read node log: %v", err)
+	}
+	if !strings.Contains(string(data), "installing node") || !strings.Contains(string(data), "Could not symlink") {
+		t.Errorf("node log = %q, want stdout and stderr", data)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "logs", "formula-jq.log")); err != nil {
+		t.Errorf("jq log should exist: %v", err)
+	}
+
+	journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	for _, e := range journal.Entries {
+		if e.Name == "node" && (e.Category != FailureKegConflict || e.Log == "") {
+			t.Errorf("node entry = %+v, want keg conflict with log", e)
+		}
+	}
+}
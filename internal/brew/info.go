@@ -0,0 +1,119 @@
+package brew
+
+import (
+	"encoding/json"
+	"os/exec"
+)
+
+// Info represents the output of "brew info --json=v2"
+type Info struct {
+	Formulae []FormulaInfo `json:"formulae"`
+	Casks    []CaskInfo    `json:"casks"`
+}
+
+// FormulaInfo represents a formula entry in "brew info --json=v2"
+type FormulaInfo struct {
+	Name         string                   `json:"name"`
+	FullName     string                   `json:"full_name"`
+	Tap          string                   `json:"tap"`
+	KegOnly      bool                     `json:"keg_only"`
+	LinkedKeg    string                   `json:"linked_keg"`
+	Pinned       bool                     `json:"pinned"`
+	Versions     FormulaVersions          `json:"versions"`
+	Dependencies []string                 `json:"dependencies"`
+	Installed    []InstalledFormula       `json:"installed"`
+	Bottle       map[string]FormulaBottle `json:"bottle"`
+	Requirements []FormulaRequirement     `json:"requirements"`
+
+	Oldnames                      []string `json:"oldnames"`
+	Oldname                       string   `json:"oldname"` // older brew versions
+	Deprecated                    bool     `json:"deprecated"`
+	DeprecationReason             string   `json:"deprecation_reason"`
+	DeprecationReplacement        string   `json:"deprecation_replacement"`
+	DeprecationReplacementFormula string   `json:"deprecation_replacement_formula"`
+	Disabled                      bool     `json:"disabled"`
+	DisableReason                 string   `json:"disable_reason"`
+	DisableReplacement            string   `json:"disable_replacement"`
+	DisableReplacementFormula     string   `json:"disable_replacement_formula"`
+}
+
+// FormulaVersions represents the available versions of a formula
+type FormulaVersions struct {
+	Stable string `json:"stable"`
+	Head   string `json:"head"`
+}
+
+// FormulaBottle represents a bottle spec (e.g., "stable") of a formula.
+// Files is keyed by bottle tag such as arm64_sonoma, sonoma, x86_64_linux or all.
+type FormulaBottle struct {
+	Files map[string]json.RawMessage `json:"files"`
+}
+
+// FormulaRequirement represents a non-formula requirement such as macos or linux
+type FormulaRequirement struct {
+	Name string `json:"name"`
+}
+
+// InstalledFormula represents an installed keg of a formula
+type InstalledFormula struct {
+	Version               string   `json:"version"`
+	UsedOptions           []string `json:"used_options"`
+	InstalledAsDependency bool     `json:"installed_as_dependency"`
+	InstalledOnRequest    bool     `json:"installed_on_request"`
+}
+
+// currentKeg returns the linked keg, or the newest installed keg
+func (f FormulaInfo) currentKeg() (InstalledFormula, bool) {
+	if len(f.Installed) == 0 {
+		return InstalledFormula{}, false
+	}
+	for _, keg := range f.Installed {
+		if keg.Version == f.LinkedKeg {
+			return keg, true
+		}
+	}
+	return f.Installed[len(f.Installed)-1], true
+}
+
+// CaskInfo represents a cask entry in "brew info --json=v2"
+type CaskInfo struct {
+	Token     string   `json:"token"`
+	FullToken string   `json:"full_token"`
+	Tap       string   `json:"tap"`
+	OldTokens []string `json:"old_tokens"`
+	// DependsOn is kept raw since its shape varies between casks
+	DependsOn json.RawMessage `json:"depends_on"`
+}
+
+// getInfo runs a "brew info --json=v2" command and parses its output
+func getInfo(cmdStr string) (*Info, error) {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseInfo(output)
+}
+
+func parseInfo(data []byte) (*Info, error) {
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// formulaInfoByName indexes formulae by both short and full name
+func formulaInfoByName(info *Info) map[string]FormulaInfo {
+	byName := make(map[string]FormulaInfo)
+	if info == nil {
+		return byName
+	}
+	for _, f := range info.Formulae {
+		byName[f.Name] = f
+		if f.FullName != "" {
+			byName[f.FullName] = f
+		}
+	}
+	return byName
+}
@@ -0,0 +1,295 @@
+package brew
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// installGroups installs groups in order, honoring --continue.
+// Each group finishes before the next one starts, so taps are always added before formulas.
+func (r *importRun) installGroups(groups []importGroup) error {
+	for _, group := range groups {
+		if err := r.installGroup(group); err != nil {
+			if !r.opts.Continue {
+				return err
+			}
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// installGroup installs every item of a group with the group's command
+func (r *importRun) installGroup(group importGroup) error {
+	opts := r.opts
+
+	if len(group.Items) == 0 {
+		if opts.Verbose && group.Label != "" {
+			fmt.Printf("Skipping %s (empty)\n", group.Label)
+		}
+		return nil
+	}
+
+	fmt.Printf("\n%s (%d items):\n", group.Label, len(group.Items))
+	r.groups = append(r.groups, group.Label)
+
+	var items []importItem
+	for _, item := range group.Items {
+		if opts.Resume && r.journal.Status(group.Label, item.Name) == JournalDone {
+			r.skipped[group.Label]++
+			if opts.DryRun {
+				fmt.Printf("  [dry-run] [done] %s%s\n", item.Name, item.suffix())
+			} else if opts.Verbose {
+				fmt.Printf("  Skipping %s (already done)\n", item.Name)
+			}
+			continue
+		}
+		if item.Installed {
+			r.present++
+			if !opts.Reinstall {
+				r.skipped[group.Label]++
+				if opts.DryRun {
+					fmt.Printf("  [dry-run] [installed] %s%s\n", item.Name, item.suffix())
+				} else {
+					fmt.Printf("  [installed] %s%s\n", item.Name, item.suffix())
+				}
+				continue
+			}
+		} else if group.Type != "service" {
+			r.toInstall++
+		}
+		items = append(items, item)
+	}
+
+	// Register the remaining items up front so an interrupted import leaves them pending
+	if !opts.DryRun {
+		for _, item := range items {
+			if r.journal.Status(group.Label, item.Name) == "" {
+				if err := r.journal.Record(group.Label, item.Name, JournalPending, nil); err != nil {
+					return fmt.Errorf("failed to write journal: %w", err)
+				}
+			}
+		}
+	}
+
+	if opts.DryRun {
+		r.printDryRun(group, items)
+		return nil
+	}
+
+	if opts.Batch && group.Batchable {
+		items = r.installBatch(group, items)
+	}
+
+	if opts.Jobs > 1 && !group.Serial {
+		return r.installParallel(group, items)
+	}
+
+	for _, item := range items {
+		if err := r.installItem(group, item, os.Stdout); err != nil {
+			if opts.Continue {
+				fmt.Printf("  Error installing %s: %v (continuing...)\n", item.Name, err)
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printDryRun prints the commands that would be run for items
+func (r *importRun) printDryRun(group importGroup, items []importItem) {
+	if r.opts.Batch && group.Batchable {
+		batch, rest := splitBatch(items)
+		if len(batch) > 1 {
+			fmt.Printf("  [dry-run] %s\n", batchCommand(group.Cmd, batch))
+			for _, item := range batch {
+				for _, post := range item.PostCmds {
+					fmt.Printf("  [dry-run] %s\n", post)
+				}
+			}
+			items = rest
+		}
+	}
+
+	for _, item := range items {
+		fmt.Printf("  [dry-run] %s%s\n", item.command(group.prefix(item)), item.suffix())
+		for _, post := range item.PostCmds {
+			fmt.Printf("  [dry-run] %s\n", post)
+		}
+	}
+}
+
+// installItem installs a single item, writing its output to w and to the
+// item's log file, and records the outcome in the journal
+func (r *importRun) installItem(group importGroup, item importItem, w io.Writer) error {
+	cmd := item.command(group.prefix(item))
+	if r.opts.Verbose {
+		fmt.Fprintf(w, "  Running: %s\n", cmd)
+	}
+
+	// The output is kept to classify failures
+	var output bytes.Buffer
+	out := io.MultiWriter(w, &output)
+
+	path := logPath(r.opts.Dir, group, item)
+	logFile, err := createLog(path)
+	if err != nil {
+		fmt.Fprintf(w, "  Warning: failed to create log %s: %v\n", path, err)
+		path = ""
+	} else {
+		defer logFile.Close()
+		fmt.Fprintf(logFile, "$ %s\n", cmd)
+		out = io.MultiWriter(w, &output, logFile)
+	}
+
+	if err := runCommandTo(cmd, out, out); err != nil {
+		category := classifyFailure(output.String())
+		if jerr := r.journal.RecordFailure(group.Label, item.Name, err, category, path); jerr != nil {
+			fmt.Fprintf(w, "  Warning: failed to write journal: %v\n", jerr)
+		}
+		return fmt.Errorf("failed to run '%s': %w (%s)", cmd, err, category)
+	}
+	r.markInstalled(group, item, w)
+	return nil
+}
+
+// markInstalled records a successful install and runs the item's post-install commands
+func (r *importRun) markInstalled(group importGroup, item importItem, w io.Writer) {
+	if err := r.journal.Record(group.Label, item.Name, JournalDone, nil); err != nil {
+		fmt.Fprintf(w, "  Warning: failed to write journal: %v\n", err)
+	}
+	fmt.Fprintf(w, "  Installed: %s%s\n", item.Name, item.suffix())
+
+	for _, post := range item.PostCmds {
+		if r.opts.Verbose {
+			fmt.Fprintf(w, "  Running: %s\n", post)
+		}
+		if err := runCommandTo(post, w, w); err != nil {
+			fmt.Fprintf(w, "  Warning: '%s' failed: %v\n", post, err)
+		}
+	}
+}
+
+// installParallel installs items with up to opts.Jobs concurrent commands.
+// Each item's output is buffered and printed as a whole once it finishes.
+func (r *importRun) installParallel(group importGroup, items []importItem) error {
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	queue := make(chan importItem)
+	for i := 0; i < r.opts.Jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				var buf bytes.Buffer
+				err := r.installItem(group, item, &buf)
+
+				mu.Lock()
+				os.Stdout.Write(buf.Bytes())
+				if err != nil {
+					if r.opts.Continue {
+						fmt.Printf("  Error installing %s: %v (continuing...)\n", item.Name, err)
+					} else if firstErr == nil {
+						firstErr = err
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, item := range items {
+		mu.Lock()
+		stop := firstErr != nil
+		mu.Unlock()
+		if stop {
+			break
+		}
+		queue <- item
+	}
+	close(queue)
+	wg.Wait()
+
+	return firstErr
+}
+
+// installBatch installs items without per-item flags with a single command.
+// It returns the items that still have to be installed one by one: those with
+// flags, or every item when the batch command fails.
+func (r *importRun) installBatch(group importGroup, items []importItem) []importItem {
+	batch, rest := splitBatch(items)
+	if len(batch) < 2 {
+		return items
+	}
+
+	cmd := batchCommand(group.Cmd, batch)
+	if r.opts.Verbose {
+		fmt.Printf("  Running: %s\n", cmd)
+	}
+	if err := runCommandExec(cmd); err != nil {
+		fmt.Printf("  Batch install failed (%v), falling back to one-by-one installs\n", err)
+		return items
+	}
+
+	for _, item := range batch {
+		r.markInstalled(group, item, os.Stdout)
+	}
+	return rest
+}
+
+// splitBatch separates items that can share a single install command from
+// those that need their own flags
+func splitBatch(items []importItem) (batch, rest []importItem) {
+	for _, item := range items {
+		if len(item.Args) == 0 && len(item.TrailingArgs) == 0 && !item.Installed {
+			batch = append(batch, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	return batch, rest
+}
+
+// batchCommand builds a single install command for several items
+func batchCommand(cmdPrefix string, items []importItem) string {
+	parts := []string{cmdPrefix}
+	for _, item := range items {
+		parts = append(parts, item.Name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// prefix returns the command prefix for an item, using the reinstall
+// command for items that are already installed
+func (group importGroup) prefix(item importItem) string {
+	if item.Installed && group.ReinstallCmd != "" {
+		return group.ReinstallCmd
+	}
+	return group.Cmd
+}
+
+// command builds the install command for an item
+func (item importItem) command(cmdPrefix string) string {
+	parts := []string{cmdPrefix}
+	parts = append(parts, item.Args...)
+	parts = append(parts, item.Name)
+	parts = append(parts, item.TrailingArgs...)
+	return strings.Join(parts, " ")
+}
+
+func (item importItem) suffix() string {
+	if item.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", item.Comment)
+}
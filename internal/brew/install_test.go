@@ -0,0 +1,196 @@
+package brew
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFormulaList(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	content := strings.Join(names, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "formula.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+}
+
+func TestImportParallel(t *testing.T) {
+	tests := []struct {
+		name       string
+		installCmd string
+		continueOn bool
+		wantErr    bool
+		wantDone   []string
+		wantFailed []string
+	}{
+		{
+			name:       "all succeed",
+			installCmd: "true",
+			wantDone:   []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:       "continue records failures",
+			installCmd: "test c !=",
+			continueOn: true,
+			wantDone:   []string{"a", "b", "d", "e"},
+			wantFailed: []string{"c"},
+		},
+		{
+			name:       "stop on failure",
+			installCmd: "false",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFormulaList(t, tmpDir, "a", "b", "c", "d", "e")
+
+			cfg := testImportConfig()
+			cfg.Brew.Import.FormulaInstallCmd = tt.installCmd
+
+			opts := ImportOptions{Dir: tmpDir, Only: "formula", Jobs: 3, Continue: tt.continueOn}
+			err := Import(cfg, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Import() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+			if err != nil {
+				t.Fatalf("loadJournal() error = %v", err)
+			}
+			for _, name := range tt.wantDone {
+				if got := journal.Status("formula.txt", name); got != JournalDone {
+					t.Errorf("%s status = %q, want %q", name, got, JournalDone)
+				}
+			}
+			for _, name := range tt.wantFailed {
+				if got := journal.Status("formula.txt", name); got != JournalFailed {
+					t.Errorf("%s status = %q, want %q", name, got, JournalFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestImportBatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFormulaList(t, tmpDir, "a", "b", "c")
+	logFile := filepath.Join(tmpDir, "install.log")
+
+	cfg := testImportConfig()
+	cfg.Brew.Import.FormulaInstallCmd = `sh -c 'echo "$@" >> ` + logFile + `' _`
+
+	if err := Import(cfg, ImportOptions{Dir: tmpDir, Only: "formula", Batch: true}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if got := string(data); got != "a b c\n" {
+		t.Errorf("install calls = %q, want a single batch call", got)
+	}
+
+	journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if got := journal.Status("formula.txt", "b"); got != JournalDone {
+		t.Errorf("b status = %q, want %q", got, JournalDone)
+	}
+}
+
+func TestImportBatchFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFormulaList(t, tmpDir, "a", "b", "c")
+	logFile := filepath.Join(tmpDir, "install.log")
+
+	// Fails when given more than one name, so the batch falls back to one-by-one installs
+	cfg := testImportConfig()
+	cfg.Brew.Import.FormulaInstallCmd = `sh -c 'test $# -eq 1 && echo "$@" >> ` + logFile + `' _`
+
+	if err := Import(cfg, ImportOptions{Dir: tmpDir, Only: "formula", Batch: true, Jobs: 2}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if got := len(strings.Fields(string(data))); got != 3 {
+		t.Errorf("installed %d items one by one, want 3", got)
+	}
+}
+
+func TestSplitBatch(t *testing.T) {
+	items := []importItem{
+		{Name: "jq"},
+		{Name: "neovim", Args: []string{"--HEAD"}},
+		{Name: "wget", PostCmds: []string{"brew pin wget"}},
+	}
+
+	batch, rest := splitBatch(items)
+	if len(batch) != 2 || batch[0].Name != "jq" || batch[1].Name != "wget" {
+		t.Errorf("batch = %v, want jq and wget", batch)
+	}
+	if len(rest) != 1 || rest[0].Name != "neovim" {
+		t.Errorf("rest = %v, want neovim", rest)
+	}
+	if got := batchCommand("brew install", batch); got != "brew install jq wget" {
+		t.Errorf("batchCommand() = %q", got)
+	}
+}
+
+func TestImportBatchDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFormulaList(t, tmpDir, "a", "b")
+
+	cfg := testImportConfig()
+	cfg.Brew.Import.FormulaListCmd = "echo b"
+
+	opts := ImportOptions{Dir: tmpDir, DryRun: true, Only: "formula", Batch: true, Jobs: 4}
+	var err error
+	output := captureStdout(t, func() { err = Import(cfg, opts) })
+	if err != nil {
+		t.Fatalf("Import() dry-run error = %v", err)
+	}
+
+	for _, want := range []string{
+		"[dry-run] [installed] b",
+		"[dry-run] brew install a",
+		"[dry-run] 1 to install, 1 already present",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, output)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, journalFile)); err == nil {
+		t.Error("dry-run should not write the journal")
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
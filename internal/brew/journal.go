@@ -0,0 +1,194 @@
+package brew
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const journalFile = "import-journal.json"
+
+// Journal status values
+const (
+	JournalPending = "pending"
+	JournalDone    = "done"
+	JournalFailed  = "failed"
+)
+
+// Journal records the outcome of each import item so an interrupted
+// import can be resumed with --resume
+type Journal struct {
+	StartedAt time.Time       `json:"started_at"`
+	Entries   []*JournalEntry `json:"entries"`
+
+	path  string
+	index map[string]*JournalEntry
+	mu    sync.Mutex
+}
+
+// JournalEntry represents the outcome of a single import item
+type JournalEntry struct {
+	Group     string    `json:"group"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Category  string    `json:"category,omitempty"` // failure category (see classifyFailure)
+	Log       string    `json:"log,omitempty"`      // path to the item's install log
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newJournal creates an empty journal stored at path
+func newJournal(path string) *Journal {
+	return &Journal{
+		StartedAt: time.Now(),
+		path:      path,
+		index:     make(map[string]*JournalEntry),
+	}
+}
+
+// loadJournal reads a journal from path, returning an empty journal if it does not exist
+func loadJournal(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newJournal(path), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	j := newJournal(path)
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %w", path, err)
+	}
+	for _, e := range j.Entries {
+		j.index[journalKey(e.Group, e.Name)] = e
+	}
+	return j, nil
+}
+
+func journalKey(group, name string) string {
+	return group + "\x00" + name
+}
+
+// Status returns the recorded status of an item, or "" if it is unknown
+func (j *Journal) Status(group, name string) string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if e, ok := j.index[journalKey(group, name)]; ok {
+		return e.Status
+	}
+	return ""
+}
+
+// Record sets the status of an item and saves the journal.
+// It is safe to call from concurrent installs.
+func (j *Journal) Record(group, name, status string, recordErr error) error {
+	return j.record(group, name, status, recordErr, "", "")
+}
+
+// RecordFailure marks an item as failed with its failure category and log path
+func (j *Journal) RecordFailure(group, name string, recordErr error, category, logPath string) error {
+	return j.record(group, name, JournalFailed, recordErr, category, logPath)
+}
+
+func (j *Journal) record(group, name, status string, recordErr error, category, logPath string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	key := journalKey(group, name)
+	e, ok := j.index[key]
+	if !ok {
+		e = &JournalEntry{Group: group, Name: name}
+		j.index[key] = e
+		j.Entries = append(j.Entries, e)
+	}
+	e.Status = status
+	e.Error = ""
+	if recordErr != nil {
+		e.Error = recordErr.Error()
+	}
+	e.Category = category
+	e.Log = logPath
+	e.UpdatedAt = time.Now()
+	return j.save()
+}
+
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, append(data, '\n'), 0644)
+}
+
+// journalCounts represents per-group totals for the summary table
+type journalCounts struct {
+	Group   string
+	Done    int
+	Failed  int
+	Pending int
+	Skipped int
+}
+
+// printImportSummary prints a per-group summary table and the failed items.
+// skipped holds the number of items per group skipped because they were
+// already done or installed; groups lists the groups of this run in order,
+// so that a group whose items were all skipped still gets a row.
+func printImportSummary(j *Journal, groups []string, skipped map[string]int) {
+	var order []string
+	counts := make(map[string]*journalCounts)
+	row := func(group string) *journalCounts {
+		c, ok := counts[group]
+		if !ok {
+			c = &journalCounts{Group: group}
+			counts[group] = c
+			order = append(order, group)
+		}
+		return c
+	}
+
+	hasEntries := make(map[string]bool)
+	for _, e := range j.Entries {
+		hasEntries[e.Group] = true
+	}
+	for _, group := range groups {
+		if hasEntries[group] || skipped[group] > 0 {
+			row(group).Skipped = skipped[group]
+		}
+	}
+	for _, e := range j.Entries {
+		c := row(e.Group)
+		switch e.Status {
+		case JournalDone:
+			c.Done++
+		case JournalFailed:
+			c.Failed++
+		default:
+			c.Pending++
+		}
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("Import Summary")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("%-28s %6s %7s %8s %8s\n", "GROUP", "DONE", "FAILED", "PENDING", "SKIPPED")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, group := range order {
+		c := counts[group]
+		fmt.Printf("%-28s %6d %7d %8d %8d\n", c.Group, c.Done, c.Failed, c.Pending, c.Skipped)
+	}
+	fmt.Println(strings.Repeat("-", 60))
+
+	var failed []*JournalEntry
+	for _, e := range j.Entries {
+		if e.Status == JournalFailed {
+			failed = append(failed, e)
+		}
+	}
+	if len(failed) > 0 {
+		printFailureReport(failed)
+		fmt.Println("\nRun again with --resume to retry failed and pending items.")
+	}
+}
@@ -0,0 +1,153 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJournalRecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journalFile)
+
+	j := newJournal(path)
+	if err := j.Record("formula.txt", "jq", JournalPending, nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := j.Record("formula.txt", "jq", JournalDone, nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := j.Record("cask.txt", "jq", JournalFailed, os.ErrNotExist); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	loaded, err := loadJournal(path)
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if len(loaded.Entries) != 2 {
+		t.Fatalf("Entries = %d, want 2", len(loaded.Entries))
+	}
+	if got := loaded.Status("formula.txt", "jq"); got != JournalDone {
+		t.Errorf("Status(formula.txt, jq) = %q, want %q", got, JournalDone)
+	}
+	if got := loaded.Status("cask.txt", "jq"); got != JournalFailed {
+		t.Errorf("Status(cask.txt, jq) = %q, want %q", got, JournalFailed)
+	}
+	if loaded.Entries[1].Error == "" {
+		t.Error("failed entry should record the error")
+	}
+	if got := loaded.Status("formula.txt", "wget"); got != "" {
+		t.Errorf("Status() of unknown item = %q, want empty", got)
+	}
+}
+
+func TestLoadJournalMissingFile(t *testing.T) {
+	j, err := loadJournal(filepath.Join(t.TempDir(), journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if len(j.Entries) != 0 {
+		t.Errorf("Entries = %d, want 0", len(j.Entries))
+	}
+}
+
+func TestLoadJournalInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journalFile)
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+	if _, err := loadJournal(path); err == nil {
+		t.Error("loadJournal() should return error for invalid JSON")
+	}
+}
+
+func TestImportResume(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("ok\nbad\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	// "test ok = <name>" only succeeds for "ok"
+	cfg := testImportConfig()
+	cfg.Brew.Import.FormulaInstallCmd = "test ok ="
+
+	opts := ImportOptions{Dir: tmpDir, Only: "formula", Continue: true}
+	if err := Import(cfg, opts); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if got := journal.Status("formula.txt", "ok"); got != JournalDone {
+		t.Errorf("ok status = %q, want %q", got, JournalDone)
+	}
+	if got := journal.Status("formula.txt", "bad"); got != JournalFailed {
+		t.Errorf("bad status = %q, want %q", got, JournalFailed)
+	}
+
+	// On resume, "ok" is skipped and only "bad" is retried; "test bad = bad" succeeds
+	cfg.Brew.Import.FormulaInstallCmd = "test bad ="
+	opts.Resume = true
+	opts.Continue = false
+	if err := Import(cfg, opts); err != nil {
+		t.Fatalf("Import() with Resume error = %v", err)
+	}
+
+	journal, err = loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if got := journal.Status("formula.txt", "bad"); got != JournalDone {
+		t.Errorf("bad status after resume = %q, want %q", got, JournalDone)
+	}
+}
+
+func TestImportWithoutResumeStartsFreshJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("jq\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	old := newJournal(filepath.Join(tmpDir, journalFile))
+	if err := old.Record("formula.txt", "removed", JournalFailed, nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	cfg := testImportConfig()
+	cfg.Brew.Import.FormulaInstallCmd = "true"
+	if err := Import(cfg, ImportOptions{Dir: tmpDir, Only: "formula"}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if got := journal.Status("formula.txt", "removed"); got != "" {
+		t.Errorf("stale entry status = %q, want empty", got)
+	}
+	if got := journal.Status("formula.txt", "jq"); got != JournalDone {
+		t.Errorf("jq status = %q, want %q", got, JournalDone)
+	}
+}
+
+func TestPrintImportSummaryAllSkippedGroup(t *testing.T) {
+	j := newJournal(filepath.Join(t.TempDir(), journalFile))
+	if err := j.Record("formula.txt", "wget", JournalDone, nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	// Every cask was already installed, so cask.txt has no journal entries
+	output := captureStdout(t, func() {
+		printImportSummary(j, []string{"formula.txt", "cask.txt"}, map[string]int{"formula.txt": 1, "cask.txt": 40})
+	})
+
+	formulaRow := strings.Index(output, "formula.txt                       1       0        0        1")
+	caskRow := strings.Index(output, "cask.txt                          0       0        0       40")
+	if formulaRow < 0 || caskRow < 0 || caskRow < formulaRow {
+		t.Errorf("summary should list formula.txt and then cask.txt:\n%s", output)
+	}
+}
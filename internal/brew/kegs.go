@@ -0,0 +1,63 @@
+package brew
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var revisionSuffix = regexp.MustCompile(`_\d+$`)
+
+// NewestKegVersion parses a line of "brew list --versions" output
+// (format: "name 1.0.0 1.1.0_1") and returns the newest keg version without
+// the brew revision suffix. brew lists kegs in directory order, not version
+// order, so the versions are compared rather than taking the last one.
+func NewestKegVersion(line string) (string, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("no installed version found")
+	}
+	newest := fields[1]
+	for _, version := range fields[2:] {
+		if compareVersions(version, newest) > 0 {
+			newest = version
+		}
+	}
+	return revisionSuffix.ReplaceAllString(newest, ""), nil
+}
+
+// compareVersions compares brew versions segment by segment, numerically
+// where both segments are numbers (3.9 < 3.10). The revision (_1) is the
+// last segment, so 1.0_1 is newer than 1.0.
+func compareVersions(a, b string) int {
+	as, bs := versionSegments(a), versionSegments(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func versionSegments(version string) []string {
+	return strings.FieldsFunc(version, func(r rune) bool {
+		return r == '.' || r == '_' || r == '-'
+	})
+}
@@ -0,0 +1,32 @@
+package brew
+
+import "testing"
+
+func TestNewestKegVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"single version", "node 20.10.0", "20.10.0", false},
+		{"newest last", "node 18.19.0 20.10.0", "20.10.0", false},
+		{"newest first", "node 20.10.0 18.19.0", "20.10.0", false},
+		{"numeric segments", "python@3 3.10.0 3.9.18", "3.10.0", false},
+		{"revisions", "openssl@3 3.3.1_10 3.3.1_2", "3.3.1", false},
+		{"revision of older version", "go 1.22.10 1.22.9_1", "1.22.10", false},
+		{"no version", "node", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewestKegVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewestKegVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("NewestKegVersion(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package brew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// MasApp represents a Mac App Store app listed by "mas list"
+type MasApp struct {
+	ID   string
+	Name string
+}
+
+var masListPattern = regexp.MustCompile(`^(\d+)\s+(.+?)(?:\s+\([^)]*\))?$`)
+
+// parseMasList parses "mas list" output
+// (format: "497799835  Xcode  (15.2)")
+func parseMasList(lines []string) []MasApp {
+	var apps []MasApp
+	for _, line := range lines {
+		m := masListPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		apps = append(apps, MasApp{ID: m[1], Name: strings.TrimSpace(m[2])})
+	}
+	return apps
+}
+
+const masFile = "mas.txt"
+
+// masLines renders mas.txt lines ("<id> <name>")
+func masLines(apps []MasApp) []string {
+	lines := make([]string, 0, len(apps))
+	for _, app := range apps {
+		lines = append(lines, app.ID+" "+app.Name)
+	}
+	return lines
+}
+
+// exportMas writes mas.txt from "mas list".
+// Failures are reported as warnings since mas is optional.
+func exportMas(cfg *config.Config, dir string) {
+	if cfg.Brew.Export.MasCmd == "" {
+		return
+	}
+
+	lines, err := runCommand(cfg.Brew.Export.MasCmd)
+	if err != nil {
+		fmt.Printf("Warning: failed to get Mac App Store apps, skipping %s: %v\n", masFile, err)
+		return
+	}
+
+	apps := parseMasList(lines)
+	if err := writeLines(filepath.Join(dir, masFile), masLines(apps)); err != nil {
+		fmt.Printf("Warning: failed to write %s: %v\n", masFile, err)
+		return
+	}
+	fmt.Printf("Exported %d Mac App Store apps to %s/%s\n", len(apps), dir, masFile)
+}
+
+// readMasGroup reads mas.txt into an import group keyed by app ID.
+// A missing file results in an empty group.
+func readMasGroup(cfg *config.Config, dir string, opts ImportOptions) (importGroup, error) {
+	group := importGroup{Label: masFile, Type: "mas", Cmd: masInstallCmd(cfg), Batchable: true}
+
+	filePath := filepath.Join(dir, masFile)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		if opts.Verbose {
+			fmt.Printf("Skipping %s (file not found)\n", masFile)
+		}
+		return group, nil
+	}
+
+	lines, err := readLines(filePath)
+	if err != nil {
+		return group, fmt.Errorf("failed to read %s: %w", masFile, err)
+	}
+
+	for _, app := range parseMasList(lines) {
+		group.Items = append(group.Items, importItem{Name: app.ID, Comment: app.Name})
+	}
+	return group, nil
+}
+
+// skipInstalledMas removes apps that "mas list" already reports as installed.
+// If mas is unavailable, the group is left unchanged.
+func skipInstalledMas(cfg *config.Config, group *importGroup, opts ImportOptions) {
+	if len(group.Items) == 0 || cfg.Brew.Import.MasListCmd == "" {
+		return
+	}
+
+	lines, err := runCommand(cfg.Brew.Import.MasListCmd)
+	if err != nil {
+		if opts.Verbose {
+			fmt.Printf("Warning: failed to list installed Mac App Store apps: %v\n", err)
+		}
+		return
+	}
+
+	installed := make(map[string]bool)
+	for _, app := range parseMasList(lines) {
+		installed[app.ID] = true
+	}
+
+	var items []importItem
+	for _, item := range group.Items {
+		if installed[item.Name] {
+			fmt.Printf("Skipping %s%s (already installed)\n", item.Name, item.suffix())
+			continue
+		}
+		items = append(items, item)
+	}
+	group.Items = items
+}
@@ -0,0 +1,81 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestParseMasList(t *testing.T) {
+	lines := []string{
+		"497799835  Xcode  (15.2)",
+		"803453959 Slack for Desktop (4.36.140)",
+		"409183694 Keynote",
+		"not an app",
+	}
+
+	expected := []MasApp{
+		{ID: "497799835", Name: "Xcode"},
+		{ID: "803453959", Name: "Slack for Desktop"},
+		{ID: "409183694", Name: "Keynote"},
+	}
+	if result := parseMasList(lines); !reflect.DeepEqual(result, expected) {
+		t.Errorf("parseMasList() = %v, want %v", result, expected)
+	}
+}
+
+func TestExportMas(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.MasCmd = "printf '497799835  Xcode  (15.2)\\n803453959  Slack  (4.36.140)\\n'"
+
+	exportMas(cfg, tmpDir)
+
+	lines, err := readLines(filepath.Join(tmpDir, masFile))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", masFile, err)
+	}
+	if expected := []string{"497799835 Xcode", "803453959 Slack"}; !reflect.DeepEqual(lines, expected) {
+		t.Errorf("%s = %v, want %v", masFile, lines, expected)
+	}
+}
+
+func TestImportOnlyMas(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, masFile), []byte("497799835 Xcode\n803453959 Slack\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", masFile, err)
+	}
+	logFile := filepath.Join(tmpDir, "install.log")
+
+	// Xcode is already installed, so only Slack is installed
+	cfg := testImportConfig()
+	cfg.Brew.Import.MasListCmd = "echo '497799835  Xcode  (15.2)'"
+	cfg.Brew.Import.MasInstallCmd = `sh -c 'echo "$@" >> ` + logFile + `' _`
+
+	if err := Import(cfg, ImportOptions{Dir: tmpDir, Only: "mas"}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if got := string(data); got != "803453959\n" {
+		t.Errorf("installed = %q, want only 803453959", got)
+	}
+}
+
+func TestSkipInstalledMasWithoutMas(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.MasListCmd = "false"
+
+	group := importGroup{Items: []importItem{{Name: "497799835", Comment: "Xcode"}}}
+	skipInstalledMas(cfg, &group, ImportOptions{})
+	if len(group.Items) != 1 {
+		t.Errorf("Items = %v, want unchanged", group.Items)
+	}
+}
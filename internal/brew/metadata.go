@@ -0,0 +1,169 @@
+package brew
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// FormulaMetadata represents a formula entry in the formula.json sidecar
+type FormulaMetadata struct {
+	Name                  string   `json:"name"`
+	Version               string   `json:"version"`
+	Pinned                bool     `json:"pinned,omitempty"`
+	InstalledAsDependency bool     `json:"installed_as_dependency,omitempty"`
+	UsedOptions           []string `json:"used_options,omitempty"`
+	Head                  bool     `json:"head,omitempty"`
+}
+
+var brewRevisionPattern = regexp.MustCompile(`_\d+$`)
+
+// metadataFile returns the sidecar file name for a list file
+// (e.g., formula.txt -> formula.json)
+func metadataFile(listFile string) string {
+	return strings.TrimSuffix(listFile, filepath.Ext(listFile)) + ".json"
+}
+
+// buildFormulaMetadata collects metadata for the exported formulas
+func buildFormulaMetadata(formulas []string, info *Info) []FormulaMetadata {
+	byName := formulaInfoByName(info)
+
+	var metadata []FormulaMetadata
+	for _, name := range formulas {
+		f, ok := byName[name]
+		if !ok {
+			continue
+		}
+		keg, ok := f.currentKeg()
+		if !ok {
+			continue
+		}
+		metadata = append(metadata, FormulaMetadata{
+			Name:                  name,
+			Version:               keg.Version,
+			Pinned:                f.Pinned,
+			InstalledAsDependency: keg.InstalledAsDependency,
+			UsedOptions:           keg.UsedOptions,
+			Head:                  strings.HasPrefix(keg.Version, "HEAD"),
+		})
+	}
+	return metadata
+}
+
+func writeFormulaMetadata(path string, metadata []FormulaMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func readFormulaMetadata(path string) (map[string]FormulaMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata []FormulaMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]FormulaMetadata)
+	for _, m := range metadata {
+		byName[m.Name] = m
+	}
+	return byName, nil
+}
+
+// exportFormulaMetadata writes formula.json next to formula.txt.
+// It is skipped when formula info is unavailable, and failures are reported
+// as warnings since the list file is already written.
+func exportFormulaMetadata(dir string, formulas []string, info *Info) {
+	if info == nil {
+		return
+	}
+
+	metadata := buildFormulaMetadata(formulas, info)
+	if err := writeFormulaMetadata(filepath.Join(dir, "formula.json"), metadata); err != nil {
+		fmt.Printf("Warning: failed to write formula.json: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported metadata for %d formulas to %s/formula.json\n", len(metadata), dir)
+}
+
+// applyFormulaMetadata adds HEAD/option flags and pin commands to formula items
+// based on the sidecar, and warns when available versions differ from the exported ones
+func applyFormulaMetadata(cfg *config.Config, dir string, group *importGroup, opts ImportOptions) error {
+	path := filepath.Join(dir, metadataFile(formulaFile(cfg)))
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	metadata, err := readFormulaMetadata(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	pinCmd := cfg.Brew.Import.PinCmd
+	if pinCmd == "" {
+		pinCmd = "brew pin"
+	}
+
+	var names []string
+	for i := range group.Items {
+		item := &group.Items[i]
+		m, ok := metadata[item.Name]
+		if !ok {
+			continue
+		}
+		names = append(names, item.Name)
+
+		if m.Head {
+			item.Args = append(item.Args, "--HEAD")
+		}
+		item.Args = append(item.Args, m.UsedOptions...)
+		if m.Pinned {
+			item.PostCmds = append(item.PostCmds, fmt.Sprintf("%s %s", pinCmd, item.Name))
+		}
+	}
+
+	if len(names) > 0 && cfg.Brew.Import.InfoCmd != "" {
+		info, err := getInfo(fmt.Sprintf("%s %s", cfg.Brew.Import.InfoCmd, strings.Join(names, " ")))
+		if err != nil {
+			if opts.Verbose {
+				fmt.Printf("Warning: failed to check available versions: %v\n", err)
+			}
+			return nil
+		}
+		for _, warning := range versionWarnings(metadata, info) {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+
+	return nil
+}
+
+// versionWarnings compares exported versions with the currently available ones
+func versionWarnings(metadata map[string]FormulaMetadata, info *Info) []string {
+	var warnings []string
+	for _, f := range info.Formulae {
+		m, ok := metadata[f.Name]
+		if !ok {
+			m, ok = metadata[f.FullName]
+		}
+		if !ok || m.Head || f.Versions.Stable == "" {
+			continue
+		}
+		exported := brewRevisionPattern.ReplaceAllString(m.Version, "")
+		if exported != f.Versions.Stable {
+			warnings = append(warnings, fmt.Sprintf("%s was exported at %s but %s is available", m.Name, m.Version, f.Versions.Stable))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,172 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const sampleInstalledInfo = `{
+  "formulae": [
+    {
+      "name": "jq",
+      "full_name": "jq",
+      "pinned": true,
+      "linked_keg": "1.7.1",
+      "versions": {"stable": "1.7.1", "head": "HEAD"},
+      "installed": [
+        {"version": "1.6", "used_options": [], "installed_as_dependency": false, "installed_on_request": true},
+        {"version": "1.7.1", "used_options": [], "installed_as_dependency": false, "installed_on_request": true}
+      ]
+    },
+    {
+      "name": "neovim",
+      "full_name": "neovim",
+      "linked_keg": "HEAD-1a2b3c4",
+      "versions": {"stable": "0.9.5", "head": "HEAD"},
+      "installed": [
+        {"version": "HEAD-1a2b3c4", "used_options": ["--with-debug"], "installed_as_dependency": true, "installed_on_request": true}
+      ]
+    }
+  ],
+  "casks": []
+}`
+
+func TestBuildFormulaMetadata(t *testing.T) {
+	info, err := parseInfo([]byte(sampleInstalledInfo))
+	if err != nil {
+		t.Fatalf("parseInfo() error = %v", err)
+	}
+
+	result := buildFormulaMetadata([]string{"jq", "neovim", "missing"}, info)
+	expected := []FormulaMetadata{
+		{Name: "jq", Version: "1.7.1", Pinned: true, UsedOptions: []string{}},
+		{Name: "neovim", Version: "HEAD-1a2b3c4", InstalledAsDependency: true, UsedOptions: []string{"--with-debug"}, Head: true},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("buildFormulaMetadata() = %#v, want %#v", result, expected)
+	}
+}
+
+func TestMetadataFile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"formula.txt", "formula.json"},
+		{"brews.list", "brews.json"},
+		{"formulas", "formulas.json"},
+	}
+
+	for _, tt := range tests {
+		if result := metadataFile(tt.input); result != tt.expected {
+			t.Errorf("metadataFile(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestWriteAndReadFormulaMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "formula.json")
+	metadata := []FormulaMetadata{
+		{Name: "jq", Version: "1.7.1", Pinned: true},
+		{Name: "neovim", Version: "HEAD-1a2b3c4", Head: true},
+	}
+
+	if err := writeFormulaMetadata(path, metadata); err != nil {
+		t.Fatalf("writeFormulaMetadata() error = %v", err)
+	}
+	result, err := readFormulaMetadata(path)
+	if err != nil {
+		t.Fatalf("readFormulaMetadata() error = %v", err)
+	}
+	if !reflect.DeepEqual(result["jq"], metadata[0]) || !reflect.DeepEqual(result["neovim"], metadata[1]) {
+		t.Errorf("readFormulaMetadata() = %v, want %v", result, metadata)
+	}
+}
+
+func TestVersionWarnings(t *testing.T) {
+	metadata := map[string]FormulaMetadata{
+		"jq":     {Name: "jq", Version: "1.6"},
+		"wget":   {Name: "wget", Version: "1.21.4_1"},
+		"neovim": {Name: "neovim", Version: "HEAD-1a2b3c4", Head: true},
+	}
+	info := &Info{Formulae: []FormulaInfo{
+		{Name: "jq", Versions: FormulaVersions{Stable: "1.7.1"}},
+		{Name: "wget", Versions: FormulaVersions{Stable: "1.21.4"}},
+		{Name: "neovim", Versions: FormulaVersions{Stable: "0.9.5"}},
+	}}
+
+	expected := []string{"jq was exported at 1.6 but 1.7.1 is available"}
+	if result := versionWarnings(metadata, info); !reflect.DeepEqual(result, expected) {
+		t.Errorf("versionWarnings() = %v, want %v", result, expected)
+	}
+}
+
+func TestApplyFormulaMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	metadata := []FormulaMetadata{
+		{Name: "jq", Version: "1.7.1", Pinned: true},
+		{Name: "neovim", Version: "HEAD-1a2b3c4", Head: true},
+	}
+	if err := writeFormulaMetadata(filepath.Join(tmpDir, "formula.json"), metadata); err != nil {
+		t.Fatalf("writeFormulaMetadata() error = %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.InfoCmd = "true"
+	group := importGroup{Cmd: "brew install", Items: []importItem{{Name: "jq"}, {Name: "neovim"}, {Name: "wget"}}}
+
+	if err := applyFormulaMetadata(cfg, tmpDir, &group, ImportOptions{}); err != nil {
+		t.Fatalf("applyFormulaMetadata() error = %v", err)
+	}
+
+	if got := group.Items[0].PostCmds; !reflect.DeepEqual(got, []string{"brew pin jq"}) {
+		t.Errorf("jq PostCmds = %v", got)
+	}
+	if got := group.Items[1].command(group.Cmd); got != "brew install --HEAD neovim" {
+		t.Errorf("neovim command = %q", got)
+	}
+	if got := group.Items[2].command(group.Cmd); got != "brew install wget" {
+		t.Errorf("wget command = %q", got)
+	}
+}
+
+func TestApplyFormulaMetadataWithoutSidecar(t *testing.T) {
+	group := importGroup{Items: []importItem{{Name: "jq"}}}
+	if err := applyFormulaMetadata(config.DefaultConfig(), t.TempDir(), &group, ImportOptions{}); err != nil {
+		t.Errorf("applyFormulaMetadata() error = %v", err)
+	}
+}
+
+func TestExportWritesFormulaMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	infoFile := filepath.Join(tmpDir, "info.json")
+	if err := os.WriteFile(infoFile, []byte(sampleInstalledInfo), 0644); err != nil {
+		t.Fatalf("failed to write info: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.FormulaCmd = "printf 'jq\\nneovim\\n'"
+	cfg.Brew.Export.CaskCmd = "true"
+	cfg.Brew.Export.TapCmd = "true"
+	cfg.Brew.Export.InfoCmd = "cat " + infoFile
+	cfg.Brew.Export.TapInfoCmd = ""
+	cfg.Brew.Export.ServicesCmd = ""
+	cfg.Brew.Export.MasCmd = ""
+
+	outDir := filepath.Join(tmpDir, "out")
+	if err := Export(cfg, ExportOptions{Dir: outDir}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	result, err := readFormulaMetadata(filepath.Join(outDir, "formula.json"))
+	if err != nil {
+		t.Fatalf("readFormulaMetadata() error = %v", err)
+	}
+	if !result["jq"].Pinned || !result["neovim"].Head {
+		t.Errorf("formula.json = %v", result)
+	}
+}
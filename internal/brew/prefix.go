@@ -0,0 +1,34 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// IsHomebrewPath reports whether a path is inside a Homebrew prefix, such
+// as a binary linked into /opt/homebrew/bin
+func IsHomebrewPath(path string) bool {
+	for _, prefix := range homebrewPrefixes() {
+		if strings.HasPrefix(path, prefix+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// homebrewPrefixes returns the Homebrew prefixes binaries may be linked from
+func homebrewPrefixes() []string {
+	var prefixes []string
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		prefixes = append(prefixes, strings.TrimSuffix(prefix, "/"))
+	}
+	prefixes = append(prefixes, "/opt/homebrew", "/home/linuxbrew/.linuxbrew")
+	// /usr/local is only a Homebrew prefix on Intel Macs; on Linux it holds
+	// system and manual installs
+	if runtime.GOOS == "darwin" {
+		prefixes = append(prefixes, "/usr/local")
+	}
+	return prefixes
+}
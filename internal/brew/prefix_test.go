@@ -0,0 +1,28 @@
+package brew
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestIsHomebrewPath(t *testing.T) {
+	t.Setenv("HOMEBREW_PREFIX", "/custom/brew/")
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/opt/homebrew/bin/python3", true},
+		{"/home/linuxbrew/.linuxbrew/bin/node", true},
+		{"/custom/brew/bin/go", true},
+		{"/opt/homebrewer/bin/go", false},
+		{"/home/user/.local/share/mise/installs/node/22/bin/node", false},
+		{"/usr/local/bin/node", runtime.GOOS == "darwin"},
+	}
+
+	for _, tt := range tests {
+		if result := IsHomebrewPath(tt.path); result != tt.expected {
+			t.Errorf("IsHomebrewPath(%q) = %v, want %v", tt.path, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package brew
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// pruneCandidate represents an installed package that is absent from the export
+type pruneCandidate struct {
+	Type string // "formula" or "cask"
+	Name string
+	Cmd  string // uninstall command
+}
+
+// prunePlan represents the packages to uninstall and those kept on purpose
+type prunePlan struct {
+	Remove []pruneCandidate
+	Kept   map[string]string // package name -> reason
+}
+
+// prune uninstalls installed-on-request formulas and casks that are not in the export.
+// Dependencies of kept packages and packages in the keep list are left alone.
+func prune(cfg *config.Config, opts ImportOptions, formulas, casks *importGroup) error {
+	plan, err := buildPrunePlan(cfg, formulas, casks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nPrune (installed but not in export):")
+	if len(plan.Kept) > 0 {
+		var names []string
+		for name := range plan.Kept {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  Keeping %s (%s)\n", name, plan.Kept[name])
+		}
+	}
+	if len(plan.Remove) == 0 {
+		fmt.Println("  Nothing to prune.")
+		return nil
+	}
+
+	if opts.DryRun {
+		for _, c := range plan.Remove {
+			fmt.Printf("  [dry-run] %s\n", c.Cmd)
+		}
+		return nil
+	}
+
+	for _, c := range plan.Remove {
+		fmt.Printf("  %s: %s\n", c.Type, c.Name)
+	}
+	fmt.Printf("\nDo you want to uninstall %d packages? [y/N]: ", len(plan.Remove))
+	var response string
+	fmt.Scanln(&response)
+	if strings.ToLower(response) != "y" {
+		fmt.Println("Prune cancelled.")
+		return nil
+	}
+
+	var failed []string
+	for _, c := range plan.Remove {
+		if opts.Verbose {
+			fmt.Printf("  Running: %s\n", c.Cmd)
+		}
+		if err := runCommandExec(c.Cmd); err != nil {
+			if !opts.Continue {
+				return fmt.Errorf("failed to run '%s': %w", c.Cmd, err)
+			}
+			fmt.Printf("  Error uninstalling %s: %v (continuing...)\n", c.Name, err)
+			failed = append(failed, c.Name)
+			continue
+		}
+		fmt.Printf("  Uninstalled: %s\n", c.Name)
+	}
+
+	fmt.Printf("\nPruned %d packages", len(plan.Remove)-len(failed))
+	if len(failed) > 0 {
+		fmt.Printf(", %d failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	fmt.Println()
+	return nil
+}
+
+// exportedGroup returns nil for an empty group so that a missing or empty
+// export file never causes every installed package to be pruned
+func exportedGroup(group importGroup) *importGroup {
+	if len(group.Items) == 0 {
+		if group.Label != "" {
+			fmt.Printf("Skipping prune for %s (no exported items)\n", group.Label)
+		}
+		return nil
+	}
+	return &group
+}
+
+// withItems returns group with the items whose names it does not have yet
+// (e.g., the current names of renamed formulas)
+func withItems(group importGroup, items []importItem) importGroup {
+	names := make(map[string]bool)
+	for _, item := range group.Items {
+		names[item.Name] = true
+	}
+	for _, item := range items {
+		if !names[item.Name] {
+			names[item.Name] = true
+			group.Items = append(group.Items, item)
+		}
+	}
+	return group
+}
+
+// buildPrunePlan compares the installed packages with the export groups.
+// A nil group is not pruned.
+func buildPrunePlan(cfg *config.Config, formulas, casks *importGroup) (*prunePlan, error) {
+	plan := &prunePlan{Kept: make(map[string]string)}
+
+	keep := make(map[string]bool)
+	for _, name := range cfg.Brew.Prune.Keep {
+		keep[name] = true
+	}
+
+	usesCmd := cfg.Brew.Prune.UsesCmd
+	if usesCmd == "" {
+		usesCmd = "brew uses --installed"
+	}
+	formulaUninstallCmd := cfg.Brew.Prune.FormulaUninstallCmd
+	if formulaUninstallCmd == "" {
+		formulaUninstallCmd = "brew uninstall"
+	}
+	caskUninstallCmd := cfg.Brew.Prune.CaskUninstallCmd
+	if caskUninstallCmd == "" {
+		caskUninstallCmd = "brew uninstall --cask"
+	}
+
+	if formulas != nil {
+		installed, err := runCommand(cfg.Brew.Export.FormulaCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get formulas: %w", err)
+		}
+
+		extra := filterKept(diffSets(groupNames(*formulas), installed).Added, keep, plan)
+		dependents := make(map[string][]string)
+		for _, name := range extra {
+			lines, err := runCommand(fmt.Sprintf("%s %s", usesCmd, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to check dependents of %s: %w", name, err)
+			}
+			for _, line := range lines {
+				dependents[name] = append(dependents[name], strings.Fields(line)...)
+			}
+		}
+
+		for _, name := range resolvePrunable(extra, dependents, plan) {
+			plan.Remove = append(plan.Remove, pruneCandidate{
+				Type: "formula",
+				Name: name,
+				Cmd:  fmt.Sprintf("%s %s", formulaUninstallCmd, name),
+			})
+		}
+	}
+
+	if casks != nil {
+		installed, err := runCommand(cfg.Brew.Export.CaskCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get casks: %w", err)
+		}
+		for _, name := range filterKept(diffSets(groupNames(*casks), installed).Added, keep, plan) {
+			plan.Remove = append(plan.Remove, pruneCandidate{
+				Type: "cask",
+				Name: name,
+				Cmd:  fmt.Sprintf("%s %s", caskUninstallCmd, name),
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// filterKept drops names in the keep list, recording them in the plan
+func filterKept(names []string, keep map[string]bool, plan *prunePlan) []string {
+	var result []string
+	for _, name := range names {
+		if keep[name] {
+			plan.Kept[name] = "in keep list"
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+// resolvePrunable returns the candidates that no remaining package depends on.
+// A candidate used by a kept package is kept as well, which may in turn keep
+// its own dependencies, so this repeats until nothing changes.
+func resolvePrunable(candidates []string, dependents map[string][]string, plan *prunePlan) []string {
+	removing := make(map[string]bool)
+	for _, name := range candidates {
+		removing[name] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, name := range candidates {
+			if !removing[name] {
+				continue
+			}
+			for _, dep := range dependents[name] {
+				if !removing[dep] {
+					removing[name] = false
+					plan.Kept[name] = "required by " + dep
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var result []string
+	for _, name := range candidates {
+		if removing[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,162 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestResolvePrunable(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		dependents map[string][]string
+		expected   []string
+		kept       []string
+	}{
+		{
+			name:       "no dependents",
+			candidates: []string{"jq", "wget"},
+			expected:   []string{"jq", "wget"},
+		},
+		{
+			name:       "used by kept package",
+			candidates: []string{"openssl@3", "wget"},
+			dependents: map[string][]string{"openssl@3": {"curl"}},
+			expected:   []string{"wget"},
+			kept:       []string{"openssl@3"},
+		},
+		{
+			name:       "used only by pruned package",
+			candidates: []string{"libidn2", "wget"},
+			dependents: map[string][]string{"libidn2": {"wget"}},
+			expected:   []string{"libidn2", "wget"},
+		},
+		{
+			name:       "kept transitively",
+			candidates: []string{"libidn2", "wget"},
+			dependents: map[string][]string{"libidn2": {"wget"}, "wget": {"git"}},
+			kept:       []string{"libidn2", "wget"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &prunePlan{Kept: make(map[string]string)}
+			result := resolvePrunable(tt.candidates, tt.dependents, plan)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("resolvePrunable() = %v, want %v", result, tt.expected)
+			}
+			for _, name := range tt.kept {
+				if _, ok := plan.Kept[name]; !ok {
+					t.Errorf("%s should be kept, kept = %v", name, plan.Kept)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPrunePlan(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.FormulaCmd = "printf 'jq\\nopenssl@3\\nwget\\ngit\\n'"
+	cfg.Brew.Export.CaskCmd = "printf 'firefox\\nslack\\n'"
+	// Only openssl@3 has an installed dependent
+	cfg.Brew.Prune.UsesCmd = `sh -c 'test "$0" = openssl@3 && echo jq || true'`
+	cfg.Brew.Prune.Keep = []string{"git"}
+
+	formulas := importGroup{Items: []importItem{{Name: "jq"}}}
+	casks := importGroup{Items: []importItem{{Name: "firefox"}}}
+
+	plan, err := buildPrunePlan(cfg, &formulas, &casks)
+	if err != nil {
+		t.Fatalf("buildPrunePlan() error = %v", err)
+	}
+
+	var cmds []string
+	for _, c := range plan.Remove {
+		cmds = append(cmds, c.Cmd)
+	}
+	expected := []string{"brew uninstall wget", "brew uninstall --cask slack"}
+	if !reflect.DeepEqual(cmds, expected) {
+		t.Errorf("Remove = %v, want %v", cmds, expected)
+	}
+	if plan.Kept["git"] != "in keep list" || plan.Kept["openssl@3"] != "required by jq" {
+		t.Errorf("Kept = %v", plan.Kept)
+	}
+}
+
+func TestImportPruneSkipsEmptyExport(t *testing.T) {
+	cfg := testImportConfig()
+	cfg.Brew.Export.FormulaCmd = "echo jq"
+	cfg.Brew.Export.CaskCmd = "false"
+
+	// No formula.txt or cask.txt: nothing may be pruned, so the failing cask command is never run
+	opts := ImportOptions{Dir: t.TempDir(), DryRun: true, Prune: true}
+	if err := Import(cfg, opts); err != nil {
+		t.Errorf("Import() with prune error = %v", err)
+	}
+}
+
+func TestImportPruneDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("jq\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	cfg := testImportConfig()
+	cfg.Brew.Export.FormulaCmd = "printf 'jq\\nwget\\n'"
+	cfg.Brew.Prune.UsesCmd = "true"
+
+	opts := ImportOptions{Dir: tmpDir, DryRun: true, Only: "formula", Prune: true}
+	if err := Import(cfg, opts); err != nil {
+		t.Errorf("Import() with prune error = %v", err)
+	}
+}
+
+func TestImportPruneKeepsResolvedFormulas(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("youtube-dl\nopenssl\njq\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	// youtube-dl is disabled and openssl is installed under its new name
+	cfg := testImportConfig()
+	cfg.Brew.Import.InfoCmd = writeRenameInfo(t, tmpDir)
+	cfg.Brew.Export.FormulaCmd = "printf 'youtube-dl\\nopenssl@3\\njq\\nwget\\n'"
+	cfg.Brew.Prune.UsesCmd = "true"
+
+	output := captureStdout(t, func() {
+		opts := ImportOptions{Dir: tmpDir, DryRun: true, Only: "formula", Prune: true}
+		if err := Import(cfg, opts); err != nil {
+			t.Errorf("Import() with prune error = %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "[dry-run] brew uninstall wget") {
+		t.Errorf("wget should be pruned:\n%s", output)
+	}
+	for _, name := range []string{"youtube-dl", "openssl@3", "jq"} {
+		if strings.Contains(output, "brew uninstall "+name+"\n") {
+			t.Errorf("%s should not be pruned:\n%s", name, output)
+		}
+	}
+}
+
+func TestBuildPrunePlanQualifiedNames(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.FormulaCmd = "echo terraform"
+	cfg.Brew.Prune.UsesCmd = "true"
+
+	formulas := importGroup{Items: []importItem{{Name: "hashicorp/tap/terraform"}}}
+	plan, err := buildPrunePlan(cfg, &formulas, nil)
+	if err != nil {
+		t.Fatalf("buildPrunePlan() error = %v", err)
+	}
+	if len(plan.Remove) != 0 {
+		t.Errorf("Remove = %v, want none", plan.Remove)
+	}
+}
@@ -0,0 +1,291 @@
+package brew
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// nameResolution describes how an exported formula name maps to the current formula
+type nameResolution struct {
+	Name        string // name as exported
+	Canonical   string // current name ("" if brew does not know the formula)
+	Deprecated  bool
+	Disabled    bool
+	Reason      string
+	Replacement string
+	Cask        string // cask the formula was migrated to ("" if it is still a formula)
+}
+
+// Renamed reports whether the formula is now known under another name
+func (r nameResolution) Renamed() bool {
+	return r.Canonical != "" && r.Canonical != r.Name
+}
+
+// Resolved reports whether brew knows the name as a formula or a cask
+func (r nameResolution) Resolved() bool {
+	return r.Canonical != "" || r.Cask != ""
+}
+
+// formulaResolution represents the result of resolving the formula group
+type formulaResolution struct {
+	Renames     map[string]string // exported name -> current name
+	MovedToCask []importItem      // formulas migrated to casks, named by cask token
+	Unresolved  []string          // names brew knows neither as a formula nor as a cask
+}
+
+// canonicalToken returns the token a cask is installed by
+func (c CaskInfo) canonicalToken() string {
+	if isCoreTap(c.Tap) || c.FullToken == "" {
+		return c.Token
+	}
+	return c.FullToken
+}
+
+// canonicalName returns the name a formula is installed by:
+// the bare name for core formulas, user/repo/name for third-party taps
+func (f FormulaInfo) canonicalName() string {
+	if isCoreTap(f.Tap) || f.FullName == "" {
+		return f.Name
+	}
+	return f.FullName
+}
+
+// knownNames returns every name the formula can be referred to by
+func (f FormulaInfo) knownNames() []string {
+	names := []string{f.Name, f.FullName}
+	names = append(names, f.Oldnames...)
+	if f.Oldname != "" {
+		names = append(names, f.Oldname)
+	}
+	return names
+}
+
+// replacement returns the suggested replacement of a deprecated or disabled formula
+func (f FormulaInfo) replacement() string {
+	for _, r := range []string{f.DisableReplacementFormula, f.DisableReplacement, f.DeprecationReplacementFormula, f.DeprecationReplacement} {
+		if r != "" {
+			return r
+		}
+	}
+	return ""
+}
+
+// lookupInfo runs brew info for the given names. Names are queried in a
+// single call; if that fails (e.g., one name is unknown), each name is
+// queried on its own so the others can still be looked up.
+func lookupInfo(infoCmd string, names []string) *Info {
+	if info, err := getInfo(fmt.Sprintf("%s %s", infoCmd, strings.Join(names, " "))); err == nil {
+		return info
+	}
+	merged := &Info{}
+	for _, name := range names {
+		if info, err := getInfo(fmt.Sprintf("%s %s", infoCmd, name)); err == nil {
+			merged.Formulae = append(merged.Formulae, info.Formulae...)
+			merged.Casks = append(merged.Casks, info.Casks...)
+		}
+	}
+	return merged
+}
+
+// resolveNames looks up exported formula names with brew info. brew info
+// follows tap migrations, so a formula moved to another tap is returned
+// under its new full name, and a formula migrated to a cask is returned
+// as a cask.
+func resolveNames(infoCmd string, names []string) map[string]nameResolution {
+	info := lookupInfo(infoCmd, names)
+
+	byName := make(map[string]FormulaInfo)
+	for _, f := range info.Formulae {
+		for _, known := range f.knownNames() {
+			if known != "" {
+				byName[known] = f
+			}
+		}
+	}
+	caskByName := make(map[string]CaskInfo)
+	for _, c := range info.Casks {
+		for _, known := range append([]string{c.Token, c.FullToken}, c.OldTokens...) {
+			if known != "" {
+				caskByName[known] = c
+			}
+		}
+	}
+
+	result := make(map[string]nameResolution)
+	for _, name := range names {
+		r := nameResolution{Name: name}
+		f, ok := byName[name]
+		if !ok {
+			f, ok = byName[bareName(name)]
+		}
+		if ok {
+			r.Canonical = f.canonicalName()
+			r.Deprecated = f.Deprecated
+			r.Disabled = f.Disabled
+			r.Reason = f.DeprecationReason
+			if f.Disabled {
+				r.Reason = f.DisableReason
+			}
+			r.Replacement = f.replacement()
+		} else if c, ok := caskByName[name]; ok {
+			r.Cask = c.canonicalToken()
+		} else if c, ok := caskByName[bareName(name)]; ok {
+			r.Cask = c.canonicalToken()
+		}
+		result[name] = r
+	}
+	return result
+}
+
+// resolveFormulas renames formula items to their current names and warns
+// about deprecated and disabled formulas. Disabled formulas cannot be
+// installed and are removed from the group, as are formulas migrated to
+// casks, which are returned to be installed as casks. Names brew does not
+// know are kept and reported as unresolved.
+func resolveFormulas(cfg *config.Config, group *importGroup, opts ImportOptions) formulaResolution {
+	result := formulaResolution{Renames: make(map[string]string)}
+	if len(group.Items) == 0 || cfg.Brew.Import.InfoCmd == "" {
+		return result
+	}
+
+	names := make([]string, 0, len(group.Items))
+	for _, item := range group.Items {
+		names = append(names, item.Name)
+	}
+	resolutions := resolveNames(cfg.Brew.Import.InfoCmd, names)
+
+	// If brew info returned nothing at all, the lookup itself failed
+	// (e.g., no network) and the names cannot be judged unresolved
+	anyResolved := false
+	for _, r := range resolutions {
+		anyResolved = anyResolved || r.Resolved()
+	}
+
+	var items []importItem
+	for _, item := range group.Items {
+		r := resolutions[item.Name]
+
+		if r.Cask != "" {
+			fmt.Printf("Note: %s has been migrated to the cask %s\n", item.Name, r.Cask)
+			item.Name = r.Cask
+			result.MovedToCask = append(result.MovedToCask, item)
+			continue
+		}
+
+		if anyResolved && !r.Resolved() {
+			fmt.Printf("Warning: %s is not a known formula or cask\n", item.Name)
+			result.Unresolved = append(result.Unresolved, item.Name)
+		}
+
+		if r.Renamed() {
+			if bareName(r.Canonical) == bareName(item.Name) {
+				fmt.Printf("Note: %s has moved to %s\n", item.Name, r.Canonical)
+			} else {
+				fmt.Printf("Note: %s has been renamed to %s\n", item.Name, r.Canonical)
+			}
+			result.Renames[item.Name] = r.Canonical
+			item.Name = r.Canonical
+		}
+
+		if r.Deprecated || r.Disabled {
+			state := "deprecated"
+			if r.Disabled {
+				state = "disabled"
+			}
+			msg := fmt.Sprintf("Warning: %s is %s", item.Name, state)
+			if r.Reason != "" {
+				msg += fmt.Sprintf(" (%s)", r.Reason)
+			}
+			if r.Replacement != "" {
+				msg += fmt.Sprintf("; use %s instead", r.Replacement)
+			}
+			fmt.Println(msg)
+		}
+
+		if r.Disabled {
+			if opts.Verbose {
+				fmt.Printf("Skipping %s (disabled formulas cannot be installed)\n", item.Name)
+			}
+			continue
+		}
+		items = append(items, item)
+	}
+	group.Items = items
+	return result
+}
+
+// printUnresolved lists the formulas brew does not know, which are
+// likely to fail to install
+func printUnresolved(unresolved []string) {
+	if len(unresolved) == 0 {
+		return
+	}
+	fmt.Printf("\nUnresolved formulas (%d): %s\n", len(unresolved), strings.Join(unresolved, ", "))
+	fmt.Println("  brew info does not know these as a formula or a cask; check for a tap that is no longer available.")
+}
+
+// rewriteFormulaNames replaces renamed formulas in the export file
+// (formula.txt, its formula.json sidecar, or the Brewfile)
+func rewriteFormulaNames(cfg *config.Config, dir, format string, renames map[string]string, opts ImportOptions) error {
+	if len(renames) == 0 {
+		return nil
+	}
+
+	var files []string
+	if format == "brewfile" {
+		files = []string{brewfileName}
+	} else {
+		files = []string{formulaFile(cfg), metadataFile(formulaFile(cfg))}
+	}
+
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		content := renameInContent(string(data), file, renames)
+		if content == string(data) {
+			continue
+		}
+
+		if opts.DryRun {
+			fmt.Printf("[dry-run] Would rewrite %s with current formula names\n", path)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		fmt.Printf("Rewrote %s with current formula names\n", path)
+	}
+	return nil
+}
+
+// renameInContent replaces formula names in a list file, a Brewfile or a JSON sidecar
+func renameInContent(content, file string, renames map[string]string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		for oldName, newName := range renames {
+			switch {
+			case file == brewfileName:
+				lines[i] = strings.Replace(line, fmt.Sprintf("brew %q", oldName), fmt.Sprintf("brew %q", newName), 1)
+			case strings.HasSuffix(file, ".json"):
+				lines[i] = strings.Replace(line, fmt.Sprintf(`"name": %q`, oldName), fmt.Sprintf(`"name": %q`, newName), 1)
+			case strings.TrimSpace(line) == oldName:
+				lines[i] = newName
+			}
+			if lines[i] != line {
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
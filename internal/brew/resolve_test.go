@@ -0,0 +1,194 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const sampleRenameInfo = `{
+  "formulae": [
+    {"name": "openssl@3", "full_name": "openssl@3", "tap": "homebrew/core", "oldnames": ["openssl"]},
+    {"name": "youtube-dl", "full_name": "youtube-dl", "tap": "homebrew/core", "disabled": true, "disable_reason": "unmaintained", "disable_replacement_formula": "yt-dlp"},
+    {"name": "jq", "full_name": "jq", "tap": "homebrew/core"},
+    {"name": "vault", "full_name": "hashicorp/tap/vault", "tap": "hashicorp/tap", "deprecated": true, "deprecation_reason": "license change"}
+  ],
+  "casks": [
+    {"token": "docker-desktop", "full_token": "docker-desktop", "tap": "homebrew/cask", "old_tokens": ["docker"]},
+    {"token": "google-cloud-sdk", "full_token": "google-cloud-sdk", "tap": "homebrew/cask"}
+  ]
+}`
+
+func writeRenameInfo(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "info.json")
+	if err := os.WriteFile(path, []byte(sampleRenameInfo), 0644); err != nil {
+		t.Fatalf("failed to write info: %v", err)
+	}
+	// Ignore the formula names passed by resolveNames
+	return `sh -c 'cat ` + path + `' _`
+}
+
+func TestResolveNames(t *testing.T) {
+	infoCmd := writeRenameInfo(t, t.TempDir())
+
+	result := resolveNames(infoCmd, []string{"openssl", "youtube-dl", "jq", "vault", "google-cloud-sdk", "docker", "unknown"})
+
+	expected := map[string]nameResolution{
+		"openssl":    {Name: "openssl", Canonical: "openssl@3"},
+		"youtube-dl": {Name: "youtube-dl", Canonical: "youtube-dl", Disabled: true, Reason: "unmaintained", Replacement: "yt-dlp"},
+		"jq":         {Name: "jq", Canonical: "jq"},
+		"vault":      {Name: "vault", Canonical: "hashicorp/tap/vault", Deprecated: true, Reason: "license change"},
+		// Migrated from formulas to casks
+		"google-cloud-sdk": {Name: "google-cloud-sdk", Cask: "google-cloud-sdk"},
+		"docker":           {Name: "docker", Cask: "docker-desktop"},
+		"unknown":          {Name: "unknown"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("resolveNames() = %+v, want %+v", result, expected)
+	}
+}
+
+func TestResolveNamesFallsBackPerName(t *testing.T) {
+	dir := t.TempDir()
+	writeRenameInfo(t, dir)
+
+	// Fails when queried with more than one name, like brew info with an unknown name
+	script := filepath.Join(dir, "info.sh")
+	content := "#!/bin/sh\ntest $# -eq 1 && cat " + filepath.Join(dir, "info.json") + "\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	result := resolveNames(script, []string{"openssl", "jq"})
+	if result["openssl"].Canonical != "openssl@3" || result["jq"].Canonical != "jq" {
+		t.Errorf("resolveNames() = %+v", result)
+	}
+}
+
+func TestResolveFormulas(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.InfoCmd = writeRenameInfo(t, t.TempDir())
+
+	group := importGroup{Items: []importItem{{Name: "openssl"}, {Name: "youtube-dl"}, {Name: "jq"}, {Name: "google-cloud-sdk"}, {Name: "unknown"}}}
+	result := resolveFormulas(cfg, &group, ImportOptions{})
+
+	if !reflect.DeepEqual(result.Renames, map[string]string{"openssl": "openssl@3"}) {
+		t.Errorf("renames = %v", result.Renames)
+	}
+	var names []string
+	for _, item := range group.Items {
+		names = append(names, item.Name)
+	}
+	// youtube-dl is disabled and cannot be installed, google-cloud-sdk is now a cask
+	if !reflect.DeepEqual(names, []string{"openssl@3", "jq", "unknown"}) {
+		t.Errorf("items = %v", names)
+	}
+	if len(result.MovedToCask) != 1 || result.MovedToCask[0].Name != "google-cloud-sdk" {
+		t.Errorf("moved to cask = %+v", result.MovedToCask)
+	}
+	if !reflect.DeepEqual(result.Unresolved, []string{"unknown"}) {
+		t.Errorf("unresolved = %v", result.Unresolved)
+	}
+}
+
+func TestResolveFormulasLookupFailed(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.InfoCmd = "false"
+
+	group := importGroup{Items: []importItem{{Name: "jq"}, {Name: "wget"}}}
+	result := resolveFormulas(cfg, &group, ImportOptions{})
+
+	// Nothing can be judged unresolved when brew info itself fails
+	if len(result.Unresolved) != 0 || len(group.Items) != 2 {
+		t.Errorf("result = %+v, items = %+v", result, group.Items)
+	}
+}
+
+func TestRenameInContent(t *testing.T) {
+	renames := map[string]string{"openssl": "openssl@3"}
+
+	tests := []struct {
+		file     string
+		input    string
+		expected string
+	}{
+		{"formula.txt", "# base\nopenssl\nopenssl@1.1\n", "# base\nopenssl@3\nopenssl@1.1\n"},
+		{"Brewfile", "brew \"openssl\", link: true\nbrew \"jq\"\n", "brew \"openssl@3\", link: true\nbrew \"jq\"\n"},
+		{"formula.json", "[\n  {\n    \"name\": \"openssl\",\n    \"version\": \"3.2\"\n  }\n]\n", "[\n  {\n    \"name\": \"openssl@3\",\n    \"version\": \"3.2\"\n  }\n]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if result := renameInContent(tt.input, tt.file, renames); result != tt.expected {
+				t.Errorf("renameInContent() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImportRewriteNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	formulaPath := filepath.Join(tmpDir, "formula.txt")
+	if err := os.WriteFile(formulaPath, []byte("openssl\njq\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	cfg := testImportConfig()
+	cfg.Brew.Import.InfoCmd = writeRenameInfo(t, tmpDir)
+	cfg.Brew.Import.FormulaInstallCmd = "true"
+
+	opts := ImportOptions{Dir: tmpDir, Only: "formula", RewriteNames: true}
+	if err := Import(cfg, opts); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	lines, err := readLines(formulaPath)
+	if err != nil {
+		t.Fatalf("failed to read formula.txt: %v", err)
+	}
+	if !reflect.DeepEqual(lines, []string{"openssl@3", "jq"}) {
+		t.Errorf("formula.txt = %v", lines)
+	}
+
+	journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if got := journal.Status("formula.txt", "openssl@3"); got != JournalDone {
+		t.Errorf("openssl@3 status = %q, want %q", got, JournalDone)
+	}
+}
+
+func TestImportMovesFormulaToCask(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("jq\ndocker\nunknown\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	cfg := testImportConfig()
+	cfg.Brew.Import.InfoCmd = writeRenameInfo(t, tmpDir)
+
+	output := captureStdout(t, func() {
+		if err := Import(cfg, ImportOptions{Dir: tmpDir, Only: "formula", DryRun: true}); err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+	})
+
+	for _, want := range []string{
+		"Note: docker has been migrated to the cask docker-desktop",
+		"[dry-run] brew install --cask docker-desktop",
+		"Unresolved formulas (1): unknown",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "brew install docker\n") {
+		t.Errorf("docker should not be installed as a formula:\n%s", output)
+	}
+}
@@ -0,0 +1,152 @@
+package brew
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// Service represents an entry from "brew services list --json"
+type Service struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	User   string `json:"user"`
+}
+
+// Running reports whether the service is currently started
+func (s Service) Running() bool {
+	return s.Status == "started"
+}
+
+// getServices runs a "brew services list --json" command and parses its output
+func getServices(cmdStr string) ([]Service, error) {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseServices(output)
+}
+
+func parseServices(data []byte) ([]Service, error) {
+	var services []Service
+	if err := json.Unmarshal(data, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// RunsAsRoot reports whether the service was started with sudo
+func (s Service) RunsAsRoot() bool {
+	return s.User == "root"
+}
+
+func writeServices(path string, services []Service) error {
+	data, err := json.MarshalIndent(services, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func readServices(path string) ([]Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseServices(data)
+}
+
+// exportServices writes services.json with the current service state.
+// Failures are reported as warnings since services are optional.
+func exportServices(cfg *config.Config, dir string) {
+	if cfg.Brew.Export.ServicesCmd == "" {
+		return
+	}
+
+	services, err := getServices(cfg.Brew.Export.ServicesCmd)
+	if err != nil {
+		fmt.Printf("Warning: failed to get services, skipping services.json: %v\n", err)
+		return
+	}
+	if err := writeServices(filepath.Join(dir, "services.json"), services); err != nil {
+		fmt.Printf("Warning: failed to write services.json: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported %d services to %s/services.json\n", len(services), dir)
+}
+
+// readServiceGroups builds restart groups for services that were running at export time.
+// Outside of dry-run, services whose formula is not installed are skipped.
+func readServiceGroups(cfg *config.Config, opts ImportOptions) ([]importGroup, error) {
+	path := filepath.Join(opts.Dir, "services.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if opts.Verbose {
+			fmt.Println("Skipping services.json (file not found)")
+		}
+		return nil, nil
+	}
+
+	services, err := readServices(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services.json: %w", err)
+	}
+
+	var installed map[string]bool
+	if !opts.DryRun {
+		installed, err = installedFormulas(cfg)
+		if err != nil {
+			fmt.Printf("Warning: failed to list installed formulas: %v\n", err)
+		}
+	}
+
+	restartCmd := cfg.Brew.Import.ServiceRestartCmd
+	if restartCmd == "" {
+		restartCmd = "brew services restart"
+	}
+	rootRestartCmd := cfg.Brew.Import.ServiceRootRestartCmd
+	if rootRestartCmd == "" {
+		rootRestartCmd = "sudo brew services restart"
+	}
+
+	userGroup := importGroup{Label: "services.json (user)", Type: "service", Cmd: restartCmd, Serial: true}
+	rootGroup := importGroup{Label: "services.json (root)", Type: "service", Cmd: rootRestartCmd, Serial: true}
+	for _, svc := range services {
+		if !svc.Running() {
+			continue
+		}
+		if installed != nil && !installed[svc.Name] {
+			fmt.Printf("Skipping service %s (formula not installed)\n", svc.Name)
+			continue
+		}
+		if svc.RunsAsRoot() {
+			rootGroup.Items = append(rootGroup.Items, importItem{Name: svc.Name})
+		} else {
+			userGroup.Items = append(userGroup.Items, importItem{Name: svc.Name})
+		}
+	}
+
+	return []importGroup{userGroup, rootGroup}, nil
+}
+
+// installedFormulas returns the set of installed formulas
+func installedFormulas(cfg *config.Config) (map[string]bool, error) {
+	cmdStr := cfg.Brew.Import.FormulaListCmd
+	if cmdStr == "" {
+		cmdStr = "brew list --formula"
+	}
+
+	lines, err := runCommand(cmdStr)
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]bool)
+	for _, line := range lines {
+		installed[line] = true
+	}
+	return installed, nil
+}
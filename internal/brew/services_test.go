@@ -0,0 +1,96 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const sampleServices = `[
+  {"name": "dnsmasq", "status": "started", "user": "root", "file": "/Library/LaunchDaemons/homebrew.mxcl.dnsmasq.plist"},
+  {"name": "postgresql@16", "status": "started", "user": "me"},
+  {"name": "redis", "status": "none", "user": null}
+]`
+
+func TestParseServices(t *testing.T) {
+	services, err := parseServices([]byte(sampleServices))
+	if err != nil {
+		t.Fatalf("parseServices() error = %v", err)
+	}
+
+	expected := []Service{
+		{Name: "dnsmasq", Status: "started", User: "root"},
+		{Name: "postgresql@16", Status: "started", User: "me"},
+		{Name: "redis", Status: "none"},
+	}
+	if !reflect.DeepEqual(services, expected) {
+		t.Errorf("parseServices() = %v, want %v", services, expected)
+	}
+
+	if !services[0].RunsAsRoot() || services[1].RunsAsRoot() {
+		t.Error("RunsAsRoot() should only be true for root services")
+	}
+	if !services[1].Running() || services[2].Running() {
+		t.Error("Running() should only be true for started services")
+	}
+}
+
+func TestReadServiceGroups(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "services.json"), []byte(sampleServices), 0644); err != nil {
+		t.Fatalf("failed to write services.json: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+
+	t.Run("dry-run lists all running services", func(t *testing.T) {
+		groups, err := readServiceGroups(cfg, ImportOptions{Dir: tmpDir, DryRun: true})
+		if err != nil {
+			t.Fatalf("readServiceGroups() error = %v", err)
+		}
+		if len(groups) != 2 {
+			t.Fatalf("groups = %d, want 2", len(groups))
+		}
+		if got := groups[0].Items[0].command(groups[0].Cmd); got != "brew services restart postgresql@16" {
+			t.Errorf("user service command = %q", got)
+		}
+		if got := groups[1].Items[0].command(groups[1].Cmd); got != "sudo brew services restart dnsmasq" {
+			t.Errorf("root service command = %q", got)
+		}
+	})
+
+	t.Run("apply skips services without installed formula", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Brew.Import.FormulaListCmd = "echo postgresql@16"
+
+		groups, err := readServiceGroups(cfg, ImportOptions{Dir: tmpDir})
+		if err != nil {
+			t.Fatalf("readServiceGroups() error = %v", err)
+		}
+		if len(groups[0].Items) != 1 || len(groups[1].Items) != 0 {
+			t.Errorf("groups = %v, want only postgresql@16", groups)
+		}
+	})
+}
+
+func TestReadServiceGroupsWithoutFile(t *testing.T) {
+	groups, err := readServiceGroups(config.DefaultConfig(), ImportOptions{Dir: t.TempDir(), DryRun: true})
+	if err != nil || groups != nil {
+		t.Errorf("readServiceGroups() = %v, %v, want nil, nil", groups, err)
+	}
+}
+
+func TestImportOnlyServicesDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "services.json"), []byte(sampleServices), 0644); err != nil {
+		t.Fatalf("failed to write services.json: %v", err)
+	}
+
+	opts := ImportOptions{Dir: tmpDir, DryRun: true, Only: "services"}
+	if err := Import(testImportConfig(), opts); err != nil {
+		t.Errorf("Import() with only=services error = %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package brew
+
+import (
+	"fmt"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// installedCasks returns the set of installed casks
+func installedCasks(cfg *config.Config) (map[string]bool, error) {
+	cmdStr := cfg.Brew.Import.CaskListCmd
+	if cmdStr == "" {
+		cmdStr = "brew list --cask"
+	}
+
+	lines, err := runCommand(cmdStr)
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]bool)
+	for _, line := range lines {
+		installed[line] = true
+	}
+	return installed, nil
+}
+
+func formulaReinstallCmd(cfg *config.Config) string {
+	if cfg.Brew.Import.FormulaReinstallCmd != "" {
+		return cfg.Brew.Import.FormulaReinstallCmd
+	}
+	return "brew reinstall"
+}
+
+func caskReinstallCmd(cfg *config.Config) string {
+	if cfg.Brew.Import.CaskReinstallCmd != "" {
+		return cfg.Brew.Import.CaskReinstallCmd
+	}
+	return "brew reinstall --cask"
+}
+
+// snapshotInstalled lists installed formulas and casks once and marks the
+// items that are already present. If a list command fails, its items are
+// left unmarked and installed as usual.
+func snapshotInstalled(cfg *config.Config, formulas, casks *importGroup, opts ImportOptions) {
+	formulas.ReinstallCmd = formulaReinstallCmd(cfg)
+	casks.ReinstallCmd = caskReinstallCmd(cfg)
+
+	for _, s := range []struct {
+		group *importGroup
+		list  func(*config.Config) (map[string]bool, error)
+		label string
+	}{
+		{formulas, installedFormulas, "formulas"},
+		{casks, installedCasks, "casks"},
+	} {
+		if len(s.group.Items) == 0 {
+			continue
+		}
+		installed, err := s.list(cfg)
+		if err != nil {
+			if opts.Verbose {
+				fmt.Printf("Warning: failed to list installed %s: %v\n", s.label, err)
+			}
+			continue
+		}
+		for i := range s.group.Items {
+			item := &s.group.Items[i]
+			item.Installed = installed[bareName(item.Name)]
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestSnapshotInstalled(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.FormulaListCmd = "printf 'jq\\nterraform\\n'"
+	cfg.Brew.Import.CaskListCmd = "false"
+
+	formulas := importGroup{Cmd: "brew install", Items: []importItem{{Name: "jq"}, {Name: "hashicorp/tap/terraform"}, {Name: "wget"}}}
+	casks := importGroup{Cmd: "brew install --cask", Items: []importItem{{Name: "firefox"}}}
+
+	snapshotInstalled(cfg, &formulas, &casks, ImportOptions{})
+
+	tests := []struct {
+		item     importItem
+		expected bool
+	}{
+		{formulas.Items[0], true},
+		{formulas.Items[1], true},
+		{formulas.Items[2], false},
+		// The cask list failed, so casks are installed as usual
+		{casks.Items[0], false},
+	}
+	for _, tt := range tests {
+		if tt.item.Installed != tt.expected {
+			t.Errorf("%s Installed = %v, want %v", tt.item.Name, tt.item.Installed, tt.expected)
+		}
+	}
+
+	if got := formulas.Items[0].command(formulas.prefix(formulas.Items[0])); got != "brew reinstall jq" {
+		t.Errorf("installed item command = %q", got)
+	}
+	if got := formulas.Items[2].command(formulas.prefix(formulas.Items[2])); got != "brew install wget" {
+		t.Errorf("missing item command = %q", got)
+	}
+}
+
+func TestImportSkipsInstalled(t *testing.T) {
+	tests := []struct {
+		name      string
+		reinstall bool
+		expected  string
+	}{
+		{"skip installed", false, "install wget\n"},
+		{"reinstall", true, "reinstall jq\ninstall wget\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("jq\nwget\n"), 0644); err != nil {
+				t.Fatalf("failed to write formula.txt: %v", err)
+			}
+			logFile := filepath.Join(tmpDir, "calls.log")
+
+			cfg := testImportConfig()
+			cfg.Brew.Import.FormulaListCmd = "echo jq"
+			cfg.Brew.Import.FormulaInstallCmd = `sh -c 'echo install "$0" >> ` + logFile + `'`
+			cfg.Brew.Import.FormulaReinstallCmd = `sh -c 'echo reinstall "$0" >> ` + logFile + `'`
+
+			opts := ImportOptions{Dir: tmpDir, Only: "formula", Reinstall: tt.reinstall}
+			if err := Import(cfg, opts); err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			data, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatalf("failed to read log: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("calls = %q, want %q", data, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImportDryRunCountsInstalled(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("jq\nwget\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Import.FormulaListCmd = "echo jq"
+
+	run, err := newImportRun(ImportOptions{Dir: tmpDir, DryRun: true})
+	if err != nil {
+		t.Fatalf("newImportRun() error = %v", err)
+	}
+	group, err := readImportGroup(tmpDir, "formula.txt", "brew install", run.opts)
+	if err != nil {
+		t.Fatalf("readImportGroup() error = %v", err)
+	}
+	group.Type = "formula"
+	snapshotInstalled(cfg, &group, &importGroup{}, run.opts)
+
+	if err := run.installGroup(group); err != nil {
+		t.Fatalf("installGroup() error = %v", err)
+	}
+	if run.toInstall != 1 || run.present != 1 {
+		t.Errorf("toInstall = %d, present = %d, want 1 and 1", run.toInstall, run.present)
+	}
+}
@@ -0,0 +1,174 @@
+package brew
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// TapInfo represents an entry in "brew tap-info --json"
+type TapInfo struct {
+	Name         string `json:"name"`
+	Remote       string `json:"remote"`
+	CustomRemote bool   `json:"custom_remote"`
+	Private      bool   `json:"private"`
+}
+
+// getTapInfo runs a "brew tap-info --json" command and parses its output
+func getTapInfo(cmdStr string) ([]TapInfo, error) {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseTapInfo(output)
+}
+
+func parseTapInfo(data []byte) ([]TapInfo, error) {
+	var taps []TapInfo
+	if err := json.Unmarshal(data, &taps); err != nil {
+		return nil, err
+	}
+	return taps, nil
+}
+
+// tapRemotes returns the remote URL of every tap that cannot be re-added
+// from its name alone (custom remotes and private taps)
+func tapRemotes(infos []TapInfo) map[string]string {
+	remotes := make(map[string]string)
+	for _, t := range infos {
+		if t.Remote != "" && (t.CustomRemote || t.Private) {
+			remotes[t.Name] = t.Remote
+		}
+	}
+	return remotes
+}
+
+// exportTapRemotes looks up custom tap remotes for export.
+// Failures are reported as warnings since taps can still be exported by name.
+func exportTapRemotes(cfg *config.Config, verbose bool) map[string]string {
+	if cfg.Brew.Export.TapInfoCmd == "" {
+		return nil
+	}
+	infos, err := getTapInfo(cfg.Brew.Export.TapInfoCmd)
+	if err != nil {
+		if verbose {
+			fmt.Printf("Warning: failed to get tap info, custom tap URLs are not recorded: %v\n", err)
+		}
+		return nil
+	}
+	return tapRemotes(infos)
+}
+
+// tapLines renders tap.txt lines, adding the remote URL for custom taps
+// (e.g., "mycompany/tools git@github.com:mycompany/homebrew-tools.git")
+func tapLines(taps []string, remotes map[string]string) []string {
+	lines := make([]string, 0, len(taps))
+	for _, tap := range taps {
+		if remote, ok := remotes[tap]; ok {
+			lines = append(lines, tap+" "+remote)
+		} else {
+			lines = append(lines, tap)
+		}
+	}
+	return lines
+}
+
+// readTapGroup reads tap.txt into an import group.
+// A line may carry the tap's remote URL after the name.
+func readTapGroup(cfg *config.Config, dir string, opts ImportOptions) (importGroup, error) {
+	group, err := readImportGroup(dir, "tap.txt", tapInstallCmd(cfg), opts)
+	if err != nil {
+		return group, err
+	}
+	group.Type = "tap"
+	group.Serial = true
+
+	for i, item := range group.Items {
+		fields := strings.Fields(item.Name)
+		group.Items[i].Name = fields[0]
+		group.Items[i].TrailingArgs = fields[1:]
+	}
+	return group, nil
+}
+
+// isCoreTap reports whether a tap is one of Homebrew's default taps,
+// whose formulas and casks are installed by their bare name
+func isCoreTap(tap string) bool {
+	return tap == "" || tap == "homebrew/core" || tap == "homebrew/cask"
+}
+
+// qualifyNames rewrites formula and cask names from third-party taps to
+// their fully-qualified user/repo/name form
+func qualifyNames(names []string, info *Info, cask bool) []string {
+	if info == nil {
+		return names
+	}
+
+	fullNames := make(map[string]string)
+	if cask {
+		for _, c := range info.Casks {
+			if !isCoreTap(c.Tap) && c.FullToken != "" {
+				fullNames[c.Token] = c.FullToken
+			}
+		}
+	} else {
+		for _, f := range info.Formulae {
+			if !isCoreTap(f.Tap) && f.FullName != "" {
+				fullNames[f.Name] = f.FullName
+			}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		if full, ok := fullNames[name]; ok {
+			result = append(result, full)
+		} else {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// tapOf returns the tap of a fully-qualified user/repo/name entry
+func tapOf(name string) (string, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+// bareName strips the tap from a fully-qualified user/repo/name entry
+func bareName(name string) string {
+	if _, ok := tapOf(name); ok {
+		return name[strings.LastIndex(name, "/")+1:]
+	}
+	return name
+}
+
+// impliedTaps returns the taps referenced by fully-qualified names in groups
+// that taps does not already add
+func impliedTaps(taps importGroup, groups ...importGroup) []importItem {
+	seen := make(map[string]bool)
+	for _, item := range taps.Items {
+		seen[item.Name] = true
+	}
+
+	var items []importItem
+	for _, group := range groups {
+		for _, item := range group.Items {
+			tap, ok := tapOf(item.Name)
+			if !ok || isCoreTap(tap) || seen[tap] {
+				continue
+			}
+			seen[tap] = true
+			items = append(items, importItem{Name: tap, Comment: "required by " + item.Name})
+		}
+	}
+	return items
+}
@@ -0,0 +1,197 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const sampleTapInfo = `[
+  {"name": "homebrew/core", "remote": "https://github.com/Homebrew/homebrew-core", "custom_remote": false, "private": false},
+  {"name": "hashicorp/tap", "remote": "https://github.com/hashicorp/homebrew-tap", "custom_remote": false, "private": false},
+  {"name": "mycompany/tools", "remote": "git@github.com:mycompany/homebrew-tools.git", "custom_remote": true, "private": true}
+]`
+
+const sampleTapFormulaInfo = `{
+  "formulae": [
+    {"name": "jq", "full_name": "jq", "tap": "homebrew/core"},
+    {"name": "terraform", "full_name": "hashicorp/tap/terraform", "tap": "hashicorp/tap"}
+  ],
+  "casks": [
+    {"token": "firefox", "full_token": "firefox", "tap": "homebrew/cask"},
+    {"token": "font-fira-code", "full_token": "homebrew/cask-fonts/font-fira-code", "tap": "homebrew/cask-fonts"}
+  ]
+}`
+
+func TestTapLines(t *testing.T) {
+	infos, err := parseTapInfo([]byte(sampleTapInfo))
+	if err != nil {
+		t.Fatalf("parseTapInfo() error = %v", err)
+	}
+
+	result := tapLines([]string{"hashicorp/tap", "mycompany/tools"}, tapRemotes(infos))
+	expected := []string{"hashicorp/tap", "mycompany/tools git@github.com:mycompany/homebrew-tools.git"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("tapLines() = %v, want %v", result, expected)
+	}
+}
+
+func TestReadTapGroup(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "hashicorp/tap\nmycompany/tools git@github.com:mycompany/homebrew-tools.git\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "tap.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write tap.txt: %v", err)
+	}
+
+	group, err := readTapGroup(config.DefaultConfig(), tmpDir, ImportOptions{})
+	if err != nil {
+		t.Fatalf("readTapGroup() error = %v", err)
+	}
+	if !group.Serial || len(group.Items) != 2 {
+		t.Fatalf("readTapGroup() = %+v", group)
+	}
+	if got := group.Items[1].command(group.Cmd); got != "brew tap mycompany/tools git@github.com:mycompany/homebrew-tools.git" {
+		t.Errorf("custom tap command = %q", got)
+	}
+	if group.Items[1].Name != "mycompany/tools" {
+		t.Errorf("custom tap name = %q", group.Items[1].Name)
+	}
+}
+
+func TestQualifyNames(t *testing.T) {
+	info, err := parseInfo([]byte(sampleTapFormulaInfo))
+	if err != nil {
+		t.Fatalf("parseInfo() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    []string
+		cask     bool
+		expected []string
+	}{
+		{"formulas", []string{"jq", "terraform"}, false, []string{"jq", "hashicorp/tap/terraform"}},
+		{"casks", []string{"firefox", "font-fira-code"}, true, []string{"firefox", "homebrew/cask-fonts/font-fira-code"}},
+		{"unknown names are kept", []string{"wget"}, false, []string{"wget"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := qualifyNames(tt.input, info, tt.cask); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("qualifyNames() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	if result := qualifyNames([]string{"terraform"}, nil, false); !reflect.DeepEqual(result, []string{"terraform"}) {
+		t.Errorf("qualifyNames() without info = %v", result)
+	}
+}
+
+func TestTapOf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"hashicorp/tap/terraform", "hashicorp/tap", true},
+		{"terraform", "", false},
+		{"hashicorp/tap", "", false},
+		{"a//b", "", false},
+	}
+
+	for _, tt := range tests {
+		tap, ok := tapOf(tt.input)
+		if tap != tt.expected || ok != tt.ok {
+			t.Errorf("tapOf(%q) = %q, %v, want %q, %v", tt.input, tap, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestImpliedTaps(t *testing.T) {
+	taps := importGroup{Items: []importItem{{Name: "hashicorp/tap"}}}
+	formulas := importGroup{Items: []importItem{
+		{Name: "jq"},
+		{Name: "hashicorp/tap/terraform"},
+		{Name: "mycompany/tools/deploy"},
+		{Name: "mycompany/tools/lint"},
+		{Name: "homebrew/core/wget"},
+	}}
+
+	result := impliedTaps(taps, formulas)
+	if len(result) != 1 || result[0].Name != "mycompany/tools" {
+		t.Errorf("impliedTaps() = %v, want only mycompany/tools", result)
+	}
+}
+
+func TestImportAutoTapsWithSkipTaps(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "formula.txt"), []byte("hashicorp/tap/terraform\n"), 0644); err != nil {
+		t.Fatalf("failed to write formula.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "tap.txt"), []byte("unused/tap\n"), 0644); err != nil {
+		t.Fatalf("failed to write tap.txt: %v", err)
+	}
+
+	cfg := testImportConfig()
+	cfg.Brew.Import.TapCmd = "true"
+	cfg.Brew.Import.FormulaInstallCmd = "true"
+
+	if err := Import(cfg, ImportOptions{Dir: tmpDir, SkipTaps: true}); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	journal, err := loadJournal(filepath.Join(tmpDir, journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if got := journal.Status("taps (from formula names)", "hashicorp/tap"); got != JournalDone {
+		t.Errorf("hashicorp/tap status = %q, want %q", got, JournalDone)
+	}
+	if got := journal.Status("tap.txt", "unused/tap"); got != "" {
+		t.Errorf("skipped tap.txt entry status = %q, want empty", got)
+	}
+}
+
+func TestExportQualifiesTapNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	infoFile := filepath.Join(tmpDir, "info.json")
+	if err := os.WriteFile(infoFile, []byte(sampleTapFormulaInfo), 0644); err != nil {
+		t.Fatalf("failed to write info: %v", err)
+	}
+	tapInfoFile := filepath.Join(tmpDir, "tap-info.json")
+	if err := os.WriteFile(tapInfoFile, []byte(sampleTapInfo), 0644); err != nil {
+		t.Fatalf("failed to write tap info: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Brew.Export.FormulaCmd = "printf 'jq\\nterraform\\n'"
+	cfg.Brew.Export.CaskCmd = "echo font-fira-code"
+	cfg.Brew.Export.TapCmd = "printf 'hashicorp/tap\\nmycompany/tools\\n'"
+	cfg.Brew.Export.InfoCmd = "cat " + infoFile
+	cfg.Brew.Export.TapInfoCmd = "cat " + tapInfoFile
+	cfg.Brew.Export.ServicesCmd = "echo '[]'"
+	cfg.Brew.Export.MasCmd = ""
+
+	outDir := filepath.Join(tmpDir, "out")
+	if err := Export(cfg, ExportOptions{Dir: outDir}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	for file, expected := range map[string][]string{
+		"formula.txt": {"jq", "hashicorp/tap/terraform"},
+		"cask.txt":    {"homebrew/cask-fonts/font-fira-code"},
+		"tap.txt":     {"hashicorp/tap", "mycompany/tools git@github.com:mycompany/homebrew-tools.git"},
+	} {
+		lines, err := readLines(filepath.Join(outDir, file))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if !reflect.DeepEqual(lines, expected) {
+			t.Errorf("%s = %v, want %v", file, lines, expected)
+		}
+	}
+}
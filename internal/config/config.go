@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -11,6 +15,8 @@ import (
 type Config struct {
 	Brew     BrewConfig     `toml:"brew"`
 	Mise     MiseConfig     `toml:"mise"`
+	Asdf     AsdfConfig     `toml:"asdf"`
+	Uv       UvConfig       `toml:"uv"`
 	Dotfiles DotfilesConfig `toml:"dotfiles"`
 	Status   StatusConfig   `toml:"status"`
 }
@@ -38,28 +44,109 @@ type ToolCheck struct {
 type BrewConfig struct {
 	Export BrewExportConfig `toml:"export"`
 	Import BrewImportConfig `toml:"import"`
+	Prune  BrewPruneConfig  `toml:"prune"`
 }
 
 // BrewExportConfig represents brew export command configuration
 type BrewExportConfig struct {
-	FormulaCmd string `toml:"formula_cmd"`
-	CaskCmd    string `toml:"cask_cmd"`
-	TapCmd     string `toml:"tap_cmd"`
+	FormulaCmd  string `toml:"formula_cmd"`
+	CaskCmd     string `toml:"cask_cmd"`
+	TapCmd      string `toml:"tap_cmd"`
+	TapInfoCmd  string `toml:"tap_info_cmd"`
+	InfoCmd     string `toml:"info_cmd"`
+	DepsCmd     string `toml:"deps_cmd"`
+	ServicesCmd string `toml:"services_cmd"`
+	MasCmd      string `toml:"mas_cmd"`
 }
 
 // BrewImportConfig represents brew import command configuration
 type BrewImportConfig struct {
-	CaskFile          string `toml:"cask_file"`
-	FormulaFile       string `toml:"formula_file"`
-	FormulaInstallCmd string `toml:"formula_install_cmd"`
-	CaskInstallCmd    string `toml:"cask_install_cmd"`
-	TapCmd            string `toml:"tap_cmd"`
+	CaskFile              string `toml:"cask_file"`
+	FormulaFile           string `toml:"formula_file"`
+	FormulaInstallCmd     string `toml:"formula_install_cmd"`
+	CaskInstallCmd        string `toml:"cask_install_cmd"`
+	TapCmd                string `toml:"tap_cmd"`
+	MasInstallCmd         string `toml:"mas_install_cmd"`
+	MasListCmd            string `toml:"mas_list_cmd"`
+	ServiceRestartCmd     string `toml:"service_restart_cmd"`
+	ServiceRootRestartCmd string `toml:"service_root_restart_cmd"`
+	LinkCmd               string `toml:"link_cmd"`
+	UnlinkCmd             string `toml:"unlink_cmd"`
+	PinCmd                string `toml:"pin_cmd"`
+	InfoCmd               string `toml:"info_cmd"`
+	FormulaListCmd        string `toml:"formula_list_cmd"`
+	CaskListCmd           string `toml:"cask_list_cmd"`
+	FormulaReinstallCmd   string `toml:"formula_reinstall_cmd"`
+	CaskReinstallCmd      string `toml:"cask_reinstall_cmd"`
+}
+
+// BrewPruneConfig represents brew import --prune configuration
+type BrewPruneConfig struct {
+	Keep                []string `toml:"keep"` // packages never uninstalled by --prune
+	UsesCmd             string   `toml:"uses_cmd"`
+	FormulaUninstallCmd string   `toml:"formula_uninstall_cmd"`
+	CaskUninstallCmd    string   `toml:"cask_uninstall_cmd"`
 }
 
 // MiseConfig represents mise-related configuration
 type MiseConfig struct {
-	Commands     MiseCommandsConfig     `toml:"commands"`
-	KnownMappings map[string]string     `toml:"known_mappings"`
+	Commands        MiseCommandsConfig     `toml:"commands"`
+	KnownMappings   map[string]MiseMapping `toml:"known_mappings"`
+	VersionStrategy string                 `toml:"version_strategy"` // latest, keep-major or keep-exact
+	Exclude         []string               `toml:"exclude"`          // brew formulas never suggested for migration
+	SmokeTests      map[string]SmokeTest   `toml:"smoke_tests"`      // tool -> post-migration check
+}
+
+// MiseMapping represents the mise tool a brew formula migrates to. In TOML
+// it is either a tool string or a table with tool and per-mapping options.
+// The tool is a registry short name (node) or a backend-qualified spec
+// (aqua:cli/cli, npm:prettier), which is used without a registry check.
+type MiseMapping struct {
+	Tool          string `toml:"tool"`
+	Version       string `toml:"version,omitempty"`        // version to install, overriding the version strategy
+	UninstallBrew *bool  `toml:"uninstall_brew,omitempty"` // false keeps the brew copy (default true)
+	SmokeTest     string `toml:"smoke_test,omitempty"`     // command overriding [mise.smoke_tests]
+}
+
+// UnmarshalTOML accepts both `formula = "tool"` and `formula = { tool = "...", version = "...", ... }`
+func (m *MiseMapping) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		m.Tool = v
+	case map[string]interface{}:
+		m.Tool, _ = v["tool"].(string)
+		m.Version, _ = v["version"].(string)
+		m.SmokeTest, _ = v["smoke_test"].(string)
+		if uninstall, ok := v["uninstall_brew"].(bool); ok {
+			m.UninstallBrew = &uninstall
+		}
+	default:
+		return fmt.Errorf("mapping must be a string or a table, got %T", data)
+	}
+	return nil
+}
+
+// SmokeTest represents a post-migration check for a tool. In TOML it is
+// either a command string or a table with command and expect.
+type SmokeTest struct {
+	Command string `toml:"command"` // its first word is the binary checked on PATH
+	Expect  string `toml:"expect"`  // regex the output must match ("" checks the exit status only)
+}
+
+// UnmarshalTOML accepts both `tool = "command"` and `tool = { command = "...", expect = "..." }`
+func (s *SmokeTest) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		s.Command = v
+	case map[string]interface{}:
+		command, _ := v["command"].(string)
+		expect, _ := v["expect"].(string)
+		s.Command = command
+		s.Expect = expect
+	default:
+		return fmt.Errorf("smoke test must be a string or a table, got %T", data)
+	}
+	return nil
 }
 
 // MiseCommandsConfig represents mise command configurations
@@ -71,17 +158,58 @@ type MiseCommandsConfig struct {
 	InstallCmd       string `toml:"install_cmd"`
 	UseGlobalCmd     string `toml:"use_global_cmd"`
 	BrewUninstallCmd string `toml:"brew_uninstall_cmd"`
+	BrewVersionsCmd  string `toml:"brew_versions_cmd"`
+	BrewUsesCmd      string `toml:"brew_uses_cmd"`
+	UninstallCmd     string `toml:"uninstall_cmd"`
+	UnuseGlobalCmd   string `toml:"unuse_global_cmd"`
+	BrewInstallCmd   string `toml:"brew_install_cmd"`
+	BrewInfoCmd      string `toml:"brew_info_cmd"`
+	ExecCmd          string `toml:"exec_cmd"`
+	WhichCmd         string `toml:"which_cmd"`
+}
+
+// AsdfConfig represents asdf-related configuration
+type AsdfConfig struct {
+	Commands         AsdfCommandsConfig `toml:"commands"`
+	ToolVersionsFile string             `toml:"tool_versions_file"`
+	KnownMappings    map[string]string  `toml:"known_mappings"`
+}
+
+// AsdfCommandsConfig represents asdf command configurations
+type AsdfCommandsConfig struct {
+	PluginListAllCmd string `toml:"plugin_list_all_cmd"`
+	PluginListCmd    string `toml:"plugin_list_cmd"`
+	PluginAddCmd     string `toml:"plugin_add_cmd"`
+	InstallCmd       string `toml:"install_cmd"`
+	CurrentCmd       string `toml:"current_cmd"`
+	BrewVersionsCmd  string `toml:"brew_versions_cmd"`
+	BrewUninstallCmd string `toml:"brew_uninstall_cmd"`
+}
+
+// UvConfig represents uv-related configuration
+type UvConfig struct {
+	Commands        UvCommandsConfig  `toml:"commands"`
+	PackageMappings map[string]string `toml:"package_mappings"` // brew formula -> PyPI package
+}
+
+// UvCommandsConfig represents uv command configurations
+type UvCommandsConfig struct {
+	BrewInfoCmd      string `toml:"brew_info_cmd"`
+	InstallCmd       string `toml:"install_cmd"`
+	ListCmd          string `toml:"list_cmd"`
+	ToolDirCmd       string `toml:"tool_dir_cmd"` // prints the uv tool bin directory
+	BrewUninstallCmd string `toml:"brew_uninstall_cmd"`
 }
 
 // DotfilesConfig represents dotfiles-related configuration
 type DotfilesConfig struct {
-	Repository  string          `toml:"repository"`
-	LocalPath   string          `toml:"local_path"`
-	SourceDir   string          `toml:"source_dir"`
-	Files       []string        `toml:"files"`
-	Directories []DirectoryMap  `toml:"directories"`
-	Symlink     bool            `toml:"symlink"`
-	Backup      bool            `toml:"backup"`
+	Repository  string         `toml:"repository"`
+	LocalPath   string         `toml:"local_path"`
+	SourceDir   string         `toml:"source_dir"`
+	Files       []string       `toml:"files"`
+	Directories []DirectoryMap `toml:"directories"`
+	Symlink     bool           `toml:"symlink"`
+	Backup      bool           `toml:"backup"`
 }
 
 // DirectoryMap represents a directory mapping from source to target
@@ -96,16 +224,38 @@ func DefaultConfig() *Config {
 	return &Config{
 		Brew: BrewConfig{
 			Export: BrewExportConfig{
-				FormulaCmd: "brew list --installed-on-request",
-				CaskCmd:    "brew list --cask",
-				TapCmd:     "brew tap",
+				FormulaCmd:  "brew list --installed-on-request",
+				CaskCmd:     "brew list --cask",
+				TapCmd:      "brew tap",
+				TapInfoCmd:  "brew tap-info --json --installed",
+				InfoCmd:     "brew info --json=v2 --installed",
+				DepsCmd:     "brew deps --installed --json",
+				ServicesCmd: "brew services list --json",
+				MasCmd:      "mas list",
 			},
 			Import: BrewImportConfig{
-				CaskFile:          "cask.txt",
-				FormulaFile:       "formula.txt",
-				FormulaInstallCmd: "brew install",
-				CaskInstallCmd:    "brew install --cask",
-				TapCmd:            "brew tap",
+				CaskFile:              "cask.txt",
+				FormulaFile:           "formula.txt",
+				FormulaInstallCmd:     "brew install",
+				CaskInstallCmd:        "brew install --cask",
+				TapCmd:                "brew tap",
+				MasInstallCmd:         "mas install",
+				MasListCmd:            "mas list",
+				ServiceRestartCmd:     "brew services restart",
+				ServiceRootRestartCmd: "sudo brew services restart",
+				LinkCmd:               "brew link --force",
+				UnlinkCmd:             "brew unlink",
+				PinCmd:                "brew pin",
+				InfoCmd:               "brew info --json=v2",
+				FormulaListCmd:        "brew list --formula",
+				CaskListCmd:           "brew list --cask",
+				FormulaReinstallCmd:   "brew reinstall",
+				CaskReinstallCmd:      "brew reinstall --cask",
+			},
+			Prune: BrewPruneConfig{
+				UsesCmd:             "brew uses --installed",
+				FormulaUninstallCmd: "brew uninstall",
+				CaskUninstallCmd:    "brew uninstall --cask",
 			},
 		},
 		Mise: MiseConfig{
@@ -114,48 +264,125 @@ func DefaultConfig() *Config {
 				RegistryJSONCmd:  "mise registry --json",
 				CurrentCmd:       "mise current",
 				ListCmd:          "mise list",
-				InstallCmd:       "mise install %s@latest",
-				UseGlobalCmd:     "mise use -g %s@latest",
+				InstallCmd:       "mise install %s",
+				UseGlobalCmd:     "mise use -g %s",
+				BrewUninstallCmd: "brew uninstall %s",
+				BrewVersionsCmd:  "brew list --versions %s",
+				BrewUsesCmd:      "brew uses --installed %s",
+				UninstallCmd:     "mise uninstall %s",
+				UnuseGlobalCmd:   "mise unuse -g %s",
+				BrewInstallCmd:   "brew install %s",
+				BrewInfoCmd:      "brew info --json=v2 %s",
+				ExecCmd:          "mise exec %s -- sh -c %s",
+				WhichCmd:         "mise which --tool %s %s",
+			},
+			VersionStrategy: "keep-major",
+			KnownMappings: map[string]MiseMapping{
+				"node":      {Tool: "node"},
+				"nodejs":    {Tool: "node"},
+				"python":    {Tool: "python"},
+				"python3":   {Tool: "python"},
+				"ruby":      {Tool: "ruby"},
+				"go":        {Tool: "go"},
+				"golang":    {Tool: "go"},
+				"rust":      {Tool: "rust"},
+				"rustup":    {Tool: "rust"},
+				"java":      {Tool: "java"},
+				"openjdk":   {Tool: "java"},
+				"deno":      {Tool: "deno"},
+				"bun":       {Tool: "bun"},
+				"terraform": {Tool: "terraform"},
+				"kubectl":   {Tool: "kubectl"},
+				"helm":      {Tool: "helm"},
+				"awscli":    {Tool: "awscli"},
+				"yarn":      {Tool: "yarn"},
+				"pnpm":      {Tool: "pnpm"},
+				"gradle":    {Tool: "gradle"},
+				"maven":     {Tool: "maven"},
+				"kotlin":    {Tool: "kotlin"},
+				"scala":     {Tool: "scala"},
+				"elixir":    {Tool: "elixir"},
+				"erlang":    {Tool: "erlang"},
+				"lua":       {Tool: "lua"},
+				"luajit":    {Tool: "luajit"},
+				"perl":      {Tool: "perl"},
+				"php":       {Tool: "php"},
+				"zig":       {Tool: "zig"},
+				"nim":       {Tool: "nim"},
+				"crystal":   {Tool: "crystal"},
+				"julia":     {Tool: "julia"},
+				"r":         {Tool: "r"},
+				"dotnet":    {Tool: "dotnet"},
+				"flutter":   {Tool: "flutter"},
+				"dart":      {Tool: "dart"},
+			},
+			SmokeTests: map[string]SmokeTest{
+				"python": {Command: "python3 -c 'import ssl, sqlite3; print(1)'", Expect: `^1$`},
+				"node":   {Command: "node -e 'console.log(1 + 1)'", Expect: `^2$`},
+				"ruby":   {Command: "ruby -ropenssl -e 'puts 1'", Expect: `^1$`},
+				"go":     {Command: "go version", Expect: `^go version go`},
+				"rust":   {Command: "rustc --version", Expect: `^rustc `},
+				"java":   {Command: "java -version", Expect: `version`},
+				"deno":   {Command: "deno --version", Expect: `^deno `},
+				"bun":    {Command: "bun --version", Expect: `^\d`},
+			},
+		},
+		Asdf: AsdfConfig{
+			Commands: AsdfCommandsConfig{
+				PluginListAllCmd: "asdf plugin list all",
+				PluginListCmd:    "asdf plugin list",
+				PluginAddCmd:     "asdf plugin add %s",
+				InstallCmd:       "asdf install %s %s",
+				CurrentCmd:       "asdf current",
+				BrewVersionsCmd:  "brew list --versions %s",
 				BrewUninstallCmd: "brew uninstall %s",
 			},
+			ToolVersionsFile: "~/.tool-versions",
 			KnownMappings: map[string]string{
-				"node":      "node",
-				"nodejs":    "node",
-				"python":    "python",
-				"python3":   "python",
-				"ruby":      "ruby",
-				"go":        "go",
-				"golang":    "go",
-				"rust":      "rust",
-				"rustup":    "rust",
-				"java":      "java",
-				"openjdk":   "java",
-				"deno":      "deno",
-				"bun":       "bun",
-				"terraform": "terraform",
-				"kubectl":   "kubectl",
-				"helm":      "helm",
-				"awscli":    "awscli",
-				"yarn":      "yarn",
-				"pnpm":      "pnpm",
-				"gradle":    "gradle",
-				"maven":     "maven",
-				"kotlin":    "kotlin",
-				"scala":     "scala",
-				"elixir":    "elixir",
-				"erlang":    "erlang",
-				"lua":       "lua",
-				"luajit":    "luajit",
-				"perl":      "perl",
-				"php":       "php",
-				"zig":       "zig",
-				"nim":       "nim",
-				"crystal":   "crystal",
-				"julia":     "julia",
-				"r":         "r",
-				"dotnet":    "dotnet",
-				"flutter":   "flutter",
-				"dart":      "dart",
+				"node":    "nodejs",
+				"nodejs":  "nodejs",
+				"python":  "python",
+				"python3": "python",
+				"ruby":    "ruby",
+				"go":      "golang",
+				"golang":  "golang",
+				"rust":    "rust",
+				"openjdk": "java",
+				"java":    "java",
+				"deno":    "deno",
+				"bun":     "bun",
+				"kubectl": "kubectl",
+				"helm":    "helm",
+				"yarn":    "yarn",
+				"pnpm":    "pnpm",
+				"gradle":  "gradle",
+				"maven":   "maven",
+				"kotlin":  "kotlin",
+				"elixir":  "elixir",
+				"erlang":  "erlang",
+				"lua":     "lua",
+				"php":     "php",
+				"zig":     "zig",
+			},
+		},
+		Uv: UvConfig{
+			Commands: UvCommandsConfig{
+				BrewInfoCmd:      "brew info --json=v2 --installed",
+				InstallCmd:       "uv tool install %s",
+				ListCmd:          "uv tool list",
+				ToolDirCmd:       "uv tool dir --bin",
+				BrewUninstallCmd: "brew uninstall %s",
+			},
+			PackageMappings: map[string]string{
+				"ansible":    "ansible",
+				"black":      "black",
+				"httpie":     "httpie",
+				"ipython":    "ipython",
+				"jupyterlab": "jupyterlab",
+				"poetry":     "poetry",
+				"pre-commit": "pre-commit",
+				"python-yq":  "yq",
+				"sphinx-doc": "sphinx",
 			},
 		},
 		Dotfiles: DotfilesConfig{
@@ -229,6 +456,134 @@ func Save(cfg *Config) error {
 	return encoder.Encode(cfg)
 }
 
+// SaveMiseExclude sets [mise] exclude in ~/.goodbye.toml. Only that key is
+// rewritten; the rest of the user's file, including comments, is kept.
+func SaveMiseExclude(exclude []string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(homeDir, ".goodbye.toml")
+	content, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	updated, err := setMiseExclude(string(content), exclude)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, []byte(updated), 0644)
+}
+
+var (
+	tableHeader  = regexp.MustCompile(`^\s*\[\[?\s*([^\]]*?)\s*\]\]?\s*(#.*)?$`)
+	excludeEntry = regexp.MustCompile(`^(\s*)exclude\s*=`)
+)
+
+// setMiseExclude returns content with [mise] exclude set to exclude,
+// adding the key or the [mise] table when missing
+func setMiseExclude(content string, exclude []string) (string, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string][]string{"exclude": exclude}); err != nil {
+		return "", err
+	}
+	entry := strings.TrimSuffix(buf.String(), "\n")
+
+	lines := strings.Split(content, "\n")
+	header := -1
+	inMise := false
+	for i := 0; i < len(lines); i++ {
+		if m := tableHeader.FindStringSubmatch(lines[i]); m != nil {
+			inMise = m[1] == "mise"
+			if inMise {
+				header = i
+			}
+			continue
+		}
+		if !inMise {
+			continue
+		}
+		m := excludeEntry.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+
+		// The old value may be an array spanning several lines
+		end, rest, err := valueEnd(lines[i:], len(m[0]))
+		if err != nil {
+			return "", err
+		}
+		replaced := append([]string{}, lines[:i]...)
+		replaced = append(replaced, m[1]+entry+rest)
+		replaced = append(replaced, lines[i+end+1:]...)
+		return validate(strings.Join(replaced, "\n"))
+	}
+
+	if header >= 0 {
+		inserted := append([]string{}, lines[:header+1]...)
+		inserted = append(inserted, entry)
+		inserted = append(inserted, lines[header+1:]...)
+		return validate(strings.Join(inserted, "\n"))
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return validate(content + "[mise]\n" + entry + "\n")
+}
+
+// valueEnd finds where the TOML value starting at lines[0][start:] ends,
+// returning the index of its last line and the text after it on that line
+func valueEnd(lines []string, start int) (int, string, error) {
+	depth := 0
+	var quote byte
+	for i, line := range lines {
+		pos := 0
+		if i == 0 {
+			pos = start
+		}
+		for ; pos < len(line); pos++ {
+			c := line[pos]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					pos++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#':
+				pos = len(line)
+			case c == '[':
+				depth++
+			case c == ']':
+				depth--
+				if depth == 0 {
+					return i, line[pos+1:], nil
+				}
+			case c != ' ' && c != '\t' && depth == 0:
+				return 0, "", fmt.Errorf("[mise] exclude must be an array")
+			}
+		}
+	}
+	return 0, "", fmt.Errorf("[mise] exclude is not a complete array")
+}
+
+// validate makes sure the edited file still decodes as a configuration
+func validate(content string) (string, error) {
+	var cfg Config
+	if _, err := toml.Decode(content, &cfg); err != nil {
+		return "", fmt.Errorf("failed to update [mise] exclude: %w", err)
+	}
+	return content, nil
+}
+
 // Load loads the configuration from ~/.goodbye.toml
 // If the file does not exist, returns the default configuration
 // User config is merged on top of defaults (partial override)
@@ -269,6 +624,21 @@ func mergeConfig(defaults, user *Config) *Config {
 	if user.Brew.Export.TapCmd != "" {
 		result.Brew.Export.TapCmd = user.Brew.Export.TapCmd
 	}
+	if user.Brew.Export.TapInfoCmd != "" {
+		result.Brew.Export.TapInfoCmd = user.Brew.Export.TapInfoCmd
+	}
+	if user.Brew.Export.InfoCmd != "" {
+		result.Brew.Export.InfoCmd = user.Brew.Export.InfoCmd
+	}
+	if user.Brew.Export.DepsCmd != "" {
+		result.Brew.Export.DepsCmd = user.Brew.Export.DepsCmd
+	}
+	if user.Brew.Export.ServicesCmd != "" {
+		result.Brew.Export.ServicesCmd = user.Brew.Export.ServicesCmd
+	}
+	if user.Brew.Export.MasCmd != "" {
+		result.Brew.Export.MasCmd = user.Brew.Export.MasCmd
+	}
 
 	// Brew Import
 	if user.Brew.Import.CaskFile != "" {
@@ -286,6 +656,56 @@ func mergeConfig(defaults, user *Config) *Config {
 	if user.Brew.Import.TapCmd != "" {
 		result.Brew.Import.TapCmd = user.Brew.Import.TapCmd
 	}
+	if user.Brew.Import.MasInstallCmd != "" {
+		result.Brew.Import.MasInstallCmd = user.Brew.Import.MasInstallCmd
+	}
+	if user.Brew.Import.MasListCmd != "" {
+		result.Brew.Import.MasListCmd = user.Brew.Import.MasListCmd
+	}
+	if user.Brew.Import.ServiceRestartCmd != "" {
+		result.Brew.Import.ServiceRestartCmd = user.Brew.Import.ServiceRestartCmd
+	}
+	if user.Brew.Import.LinkCmd != "" {
+		result.Brew.Import.LinkCmd = user.Brew.Import.LinkCmd
+	}
+	if user.Brew.Import.UnlinkCmd != "" {
+		result.Brew.Import.UnlinkCmd = user.Brew.Import.UnlinkCmd
+	}
+	if user.Brew.Import.PinCmd != "" {
+		result.Brew.Import.PinCmd = user.Brew.Import.PinCmd
+	}
+	if user.Brew.Import.InfoCmd != "" {
+		result.Brew.Import.InfoCmd = user.Brew.Import.InfoCmd
+	}
+	if user.Brew.Import.ServiceRootRestartCmd != "" {
+		result.Brew.Import.ServiceRootRestartCmd = user.Brew.Import.ServiceRootRestartCmd
+	}
+	if user.Brew.Import.FormulaListCmd != "" {
+		result.Brew.Import.FormulaListCmd = user.Brew.Import.FormulaListCmd
+	}
+	if user.Brew.Import.CaskListCmd != "" {
+		result.Brew.Import.CaskListCmd = user.Brew.Import.CaskListCmd
+	}
+	if user.Brew.Import.FormulaReinstallCmd != "" {
+		result.Brew.Import.FormulaReinstallCmd = user.Brew.Import.FormulaReinstallCmd
+	}
+	if user.Brew.Import.CaskReinstallCmd != "" {
+		result.Brew.Import.CaskReinstallCmd = user.Brew.Import.CaskReinstallCmd
+	}
+
+	// Brew Prune
+	if len(user.Brew.Prune.Keep) > 0 {
+		result.Brew.Prune.Keep = user.Brew.Prune.Keep
+	}
+	if user.Brew.Prune.UsesCmd != "" {
+		result.Brew.Prune.UsesCmd = user.Brew.Prune.UsesCmd
+	}
+	if user.Brew.Prune.FormulaUninstallCmd != "" {
+		result.Brew.Prune.FormulaUninstallCmd = user.Brew.Prune.FormulaUninstallCmd
+	}
+	if user.Brew.Prune.CaskUninstallCmd != "" {
+		result.Brew.Prune.CaskUninstallCmd = user.Brew.Prune.CaskUninstallCmd
+	}
 
 	// Mise Commands
 	if user.Mise.Commands.RegistryCmd != "" {
@@ -309,6 +729,36 @@ func mergeConfig(defaults, user *Config) *Config {
 	if user.Mise.Commands.BrewUninstallCmd != "" {
 		result.Mise.Commands.BrewUninstallCmd = user.Mise.Commands.BrewUninstallCmd
 	}
+	if user.Mise.Commands.BrewVersionsCmd != "" {
+		result.Mise.Commands.BrewVersionsCmd = user.Mise.Commands.BrewVersionsCmd
+	}
+	if user.Mise.Commands.BrewUsesCmd != "" {
+		result.Mise.Commands.BrewUsesCmd = user.Mise.Commands.BrewUsesCmd
+	}
+	if user.Mise.Commands.UninstallCmd != "" {
+		result.Mise.Commands.UninstallCmd = user.Mise.Commands.UninstallCmd
+	}
+	if user.Mise.Commands.UnuseGlobalCmd != "" {
+		result.Mise.Commands.UnuseGlobalCmd = user.Mise.Commands.UnuseGlobalCmd
+	}
+	if user.Mise.Commands.BrewInstallCmd != "" {
+		result.Mise.Commands.BrewInstallCmd = user.Mise.Commands.BrewInstallCmd
+	}
+	if user.Mise.Commands.BrewInfoCmd != "" {
+		result.Mise.Commands.BrewInfoCmd = user.Mise.Commands.BrewInfoCmd
+	}
+	if user.Mise.Commands.ExecCmd != "" {
+		result.Mise.Commands.ExecCmd = user.Mise.Commands.ExecCmd
+	}
+	if user.Mise.Commands.WhichCmd != "" {
+		result.Mise.Commands.WhichCmd = user.Mise.Commands.WhichCmd
+	}
+	if user.Mise.VersionStrategy != "" {
+		result.Mise.VersionStrategy = user.Mise.VersionStrategy
+	}
+	if len(user.Mise.Exclude) > 0 {
+		result.Mise.Exclude = user.Mise.Exclude
+	}
 
 	// Mise KnownMappings - merge maps (user overrides defaults for same keys)
 	if user.Mise.KnownMappings != nil {
@@ -317,6 +767,70 @@ func mergeConfig(defaults, user *Config) *Config {
 		}
 	}
 
+	// Mise SmokeTests - merge maps (user overrides defaults for same keys)
+	if user.Mise.SmokeTests != nil {
+		for k, v := range user.Mise.SmokeTests {
+			result.Mise.SmokeTests[k] = v
+		}
+	}
+
+	// Asdf Commands
+	if user.Asdf.Commands.PluginListAllCmd != "" {
+		result.Asdf.Commands.PluginListAllCmd = user.Asdf.Commands.PluginListAllCmd
+	}
+	if user.Asdf.Commands.PluginListCmd != "" {
+		result.Asdf.Commands.PluginListCmd = user.Asdf.Commands.PluginListCmd
+	}
+	if user.Asdf.Commands.PluginAddCmd != "" {
+		result.Asdf.Commands.PluginAddCmd = user.Asdf.Commands.PluginAddCmd
+	}
+	if user.Asdf.Commands.InstallCmd != "" {
+		result.Asdf.Commands.InstallCmd = user.Asdf.Commands.InstallCmd
+	}
+	if user.Asdf.Commands.CurrentCmd != "" {
+		result.Asdf.Commands.CurrentCmd = user.Asdf.Commands.CurrentCmd
+	}
+	if user.Asdf.Commands.BrewVersionsCmd != "" {
+		result.Asdf.Commands.BrewVersionsCmd = user.Asdf.Commands.BrewVersionsCmd
+	}
+	if user.Asdf.Commands.BrewUninstallCmd != "" {
+		result.Asdf.Commands.BrewUninstallCmd = user.Asdf.Commands.BrewUninstallCmd
+	}
+	if user.Asdf.ToolVersionsFile != "" {
+		result.Asdf.ToolVersionsFile = user.Asdf.ToolVersionsFile
+	}
+
+	// Asdf KnownMappings - merge maps (user overrides defaults for same keys)
+	if user.Asdf.KnownMappings != nil {
+		for k, v := range user.Asdf.KnownMappings {
+			result.Asdf.KnownMappings[k] = v
+		}
+	}
+
+	// Uv Commands
+	if user.Uv.Commands.BrewInfoCmd != "" {
+		result.Uv.Commands.BrewInfoCmd = user.Uv.Commands.BrewInfoCmd
+	}
+	if user.Uv.Commands.InstallCmd != "" {
+		result.Uv.Commands.InstallCmd = user.Uv.Commands.InstallCmd
+	}
+	if user.Uv.Commands.ListCmd != "" {
+		result.Uv.Commands.ListCmd = user.Uv.Commands.ListCmd
+	}
+	if user.Uv.Commands.ToolDirCmd != "" {
+		result.Uv.Commands.ToolDirCmd = user.Uv.Commands.ToolDirCmd
+	}
+	if user.Uv.Commands.BrewUninstallCmd != "" {
+		result.Uv.Commands.BrewUninstallCmd = user.Uv.Commands.BrewUninstallCmd
+	}
+
+	// Uv PackageMappings - merge maps (user overrides defaults for same keys)
+	if user.Uv.PackageMappings != nil {
+		for k, v := range user.Uv.PackageMappings {
+			result.Uv.PackageMappings[k] = v
+		}
+	}
+
 	// Dotfiles
 	if user.Dotfiles.Repository != "" {
 		result.Dotfiles.Repository = user.Dotfiles.Repository
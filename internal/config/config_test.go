@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetMiseExclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "no file",
+			input:    "",
+			expected: "[mise]\nexclude = [\"node\", \"go\"]\n",
+		},
+		{
+			name:     "no mise table",
+			input:    "# my settings\n[brew.export]\ndir = \"~/dotfiles\"\n",
+			expected: "# my settings\n[brew.export]\ndir = \"~/dotfiles\"\n\n[mise]\nexclude = [\"node\", \"go\"]\n",
+		},
+		{
+			name:     "mise table without exclude",
+			input:    "[mise]\n# pin everything\nversion_strategy = \"keep-exact\"\n\n[mise.known_mappings]\nnode = \"node\"\n",
+			expected: "[mise]\nexclude = [\"node\", \"go\"]\n# pin everything\nversion_strategy = \"keep-exact\"\n\n[mise.known_mappings]\nnode = \"node\"\n",
+		},
+		{
+			name:     "existing exclude",
+			input:    "[mise]\nexclude = [\"node\"] # keep brew node\nversion_strategy = \"latest\"\n",
+			expected: "[mise]\nexclude = [\"node\", \"go\"] # keep brew node\nversion_strategy = \"latest\"\n",
+		},
+		{
+			name:     "multi-line exclude",
+			input:    "[mise]\nexclude = [\n  \"node\", # needed by work scripts\n  \"python]\",\n]\n\n[uv]\n",
+			expected: "[mise]\nexclude = [\"node\", \"go\"]\n\n[uv]\n",
+		},
+		{
+			name:     "exclude in another table",
+			input:    "[dotfiles]\nexclude = [\".DS_Store\"]\n\n[mise]\n",
+			expected: "[dotfiles]\nexclude = [\".DS_Store\"]\n\n[mise]\nexclude = [\"node\", \"go\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := setMiseExclude(tt.input, []string{"node", "go"})
+			if err != nil {
+				t.Fatalf("setMiseExclude() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("setMiseExclude() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSaveMiseExclude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	configPath := filepath.Join(home, ".goodbye.toml")
+	if err := os.WriteFile(configPath, []byte("# mine\n[mise]\nversion_strategy = \"keep-major\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if err := SaveMiseExclude([]string{"node"}); err != nil {
+		t.Fatalf("SaveMiseExclude() error = %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if string(content) != "# mine\n[mise]\nexclude = [\"node\"]\nversion_strategy = \"keep-major\"\n" {
+		t.Errorf("config = %q", content)
+	}
+
+	// Defaults are merged on load, not written to the file
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Mise.Exclude, []string{"node"}) || cfg.Mise.VersionStrategy != "keep-major" {
+		t.Errorf("Mise = %+v", cfg.Mise)
+	}
+}
@@ -1,11 +1,12 @@
 package mise
 
 import (
-	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -15,12 +16,37 @@ type ExportOptions struct {
 	DryRun  bool
 	Verbose bool
 	Format  string // "toml" or "tool-versions"
+
+	ActiveOnly  bool   // export only versions active in the current directory
+	VersionMode string // requested (default) or exact
 }
 
+// Version modes for the mise export
+const (
+	VersionRequested = "requested" // write the requested spec (e.g., 20, lts) of versions set by a config
+	VersionExact     = "exact"     // write the resolved version (e.g., 20.10.0) of versions set by a config
+)
+
 // InstalledTool represents an installed mise tool
 type InstalledTool struct {
-	Name    string
-	Version string
+	Name      string
+	Version   string
+	Requested string // version spec in the config that set it ("" if no config requests it)
+	Active    bool   // active in the current directory
+	Installed bool
+	Source    string // path of the config that requests the version
+}
+
+// miseLsEntry represents a version in the output of 'mise ls --json'
+type miseLsEntry struct {
+	Version          string `json:"version"`
+	RequestedVersion string `json:"requested_version"`
+	Installed        bool   `json:"installed"`
+	Active           bool   `json:"active"`
+	Source           struct {
+		Type string `json:"type"`
+		Path string `json:"path"`
+	} `json:"source"`
 }
 
 // Export exports the current mise environment to files
@@ -41,30 +67,53 @@ func Export(opts ExportOptions) error {
 	if opts.Format == "" {
 		opts.Format = "toml"
 	}
+	if opts.VersionMode == "" {
+		opts.VersionMode = VersionRequested
+	}
+	if opts.VersionMode != VersionRequested && opts.VersionMode != VersionExact {
+		return fmt.Errorf("invalid version mode: %s (must be requested or exact)", opts.VersionMode)
+	}
 
 	// Get installed tools
-	tools, err := GetInstalledTools()
+	installed, err := GetInstalledTools()
 	if err != nil {
 		return fmt.Errorf("failed to get installed tools: %w", err)
 	}
 
-	if len(tools) == 0 {
+	if len(installed) == 0 {
 		fmt.Println("No tools installed in mise.")
 		return nil
 	}
 
+	tools := exportVersions(installed, opts)
+	if len(tools) == 0 {
+		fmt.Printf("No tools to export (%s versions", opts.VersionMode)
+		if opts.ActiveOnly {
+			fmt.Print(", active only")
+		}
+		fmt.Println(").")
+		return nil
+	}
+
 	if opts.DryRun {
 		fmt.Println("[dry-run] Would create directory:", opts.Dir)
-		fmt.Printf("[dry-run] Found %d installed tools:\n", len(tools))
+		fmt.Printf("[dry-run] Found %d tools to export (%s versions):\n", len(tools), opts.VersionMode)
 		for _, tool := range tools {
+			if opts.Verbose && tool.Source != "" {
+				fmt.Printf("  - %s@%s (set by %s)\n", tool.Name, tool.Version, tool.Source)
+				continue
+			}
 			fmt.Printf("  - %s@%s\n", tool.Name, tool.Version)
 		}
 
 		switch opts.Format {
 		case "toml":
+			content, err := exportTOML(opts.Dir, tools)
+			if err != nil {
+				return err
+			}
 			fmt.Printf("\n[dry-run] Would create file: %s/.mise.toml\n", opts.Dir)
 			fmt.Println("[dry-run] Content preview:")
-			content := GenerateTOML(tools)
 			for _, line := range strings.Split(content, "\n") {
 				fmt.Printf("  %s\n", line)
 			}
@@ -92,7 +141,10 @@ func Export(opts ExportOptions) error {
 	switch opts.Format {
 	case "toml":
 		filename = ".mise.toml"
-		content = GenerateTOML(tools)
+		content, err = exportTOML(opts.Dir, tools)
+		if err != nil {
+			return err
+		}
 	case "tool-versions":
 		filename = ".tool-versions"
 		content = GenerateToolVersions(tools)
@@ -110,64 +162,149 @@ func Export(opts ExportOptions) error {
 	return nil
 }
 
-// GetInstalledTools returns the list of installed mise tools
+// exportTOML generates .mise.toml content for dir. The [env], [tasks],
+// [settings] and [plugins] sections and the tool options of an existing
+// .mise.toml are kept.
+func exportTOML(dir string, tools []InstalledTool) (string, error) {
+	path := filepath.Join(dir, ".mise.toml")
+	existing, err := readConfigFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return generateTOMLFile(existing, tools)
+}
+
+// GetInstalledTools returns the installed and requested mise tools
+// with their requested version, active state and source config
 func GetInstalledTools() ([]InstalledTool, error) {
-	cmd := exec.Command("mise", "ls", "--installed")
+	cmd := exec.Command("mise", "ls", "--json")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("mise command failed (is mise installed?): %w", err)
 	}
 
-	return ParseMiseLsOutput(string(output)), nil
+	return ParseMiseLsJSON(output)
 }
 
-// ParseMiseLsOutput parses the output of 'mise ls --installed'
-func ParseMiseLsOutput(output string) []InstalledTool {
+// ParseMiseLsJSON parses the output of 'mise ls --json', sorted by tool name
+func ParseMiseLsJSON(output []byte) ([]InstalledTool, error) {
+	var entries map[string][]miseLsEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("invalid mise ls output: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	var tools []InstalledTool
-	seen := make(map[string]bool)
+	for _, name := range names {
+		for _, e := range entries[name] {
+			tools = append(tools, InstalledTool{
+				Name:      name,
+				Version:   e.Version,
+				Requested: e.RequestedVersion,
+				Active:    e.Active,
+				Installed: e.Installed,
+				Source:    e.Source.Path,
+			})
+		}
+	}
+	return tools, nil
+}
 
-	scanner := bufio.NewScanner(strings.NewReader(output))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
+// exportVersions returns the tools to export with the version to write.
+// Only versions requested by a config are exported, so stale installs are
+// left out: requested mode writes the requested spec once per spec, and
+// exact mode writes the version it resolved to.
+func exportVersions(tools []InstalledTool, opts ExportOptions) []InstalledTool {
+	var result []InstalledTool
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		if opts.ActiveOnly && !tool.Active {
 			continue
 		}
 
-		// Parse line: "node  20.10.0  ~/.local/share/mise/installs/node/20.10.0"
-		// or: "python 3.12.0 (set by ~/.config/mise/config.toml)"
-		fields := strings.Fields(line)
-		if len(fields) >= 2 {
-			name := fields[0]
-			version := fields[1]
-
-			// Skip if already seen (take the first version for each tool)
-			key := name + "@" + version
-			if seen[key] {
+		// Versions no config requests are stale installs
+		if tool.Requested == "" && tool.Source == "" {
+			continue
+		}
+		if opts.VersionMode != VersionExact {
+			if tool.Requested == "" {
 				continue
 			}
-			seen[key] = true
+			tool.Version = tool.Requested
+		}
 
-			tools = append(tools, InstalledTool{
-				Name:    name,
-				Version: version,
-			})
+		key := tool.Name + "@" + tool.Version
+		if seen[key] {
+			continue
 		}
+		seen[key] = true
+		result = append(result, tool)
 	}
-
-	return tools
+	return result
 }
 
 // GenerateTOML generates .mise.toml content from installed tools
 func GenerateTOML(tools []InstalledTool) string {
-	var sb strings.Builder
-	sb.WriteString("# Generated by goodbye export mise\n")
-	sb.WriteString("[tools]\n")
+	// Installed tools only have version strings, which always encode
+	content, _ := generateTOMLFile(&ConfigFile{}, tools)
+	return content
+}
+
+// generateTOMLFile generates .mise.toml content from installed tools,
+// keeping the tool options and the other sections of an existing file
+func generateTOMLFile(existing *ConfigFile, tools []InstalledTool) (string, error) {
+	f := *existing
+	f.Tools = mergeTools(existing.Tools, tools)
+
+	content, err := f.Encode()
+	if err != nil {
+		return "", err
+	}
+	return "# Generated by goodbye export mise\n" + content, nil
+}
+
+// mergeTools groups installed tools by name in order. The options of an
+// existing spec are kept for the same version, or when the tool has a
+// single version in both.
+func mergeTools(existing []Tool, tools []InstalledTool) []Tool {
+	specsOf := make(map[string]ToolSpecs)
+	for _, tool := range existing {
+		specsOf[tool.Name] = tool.Specs
+	}
 
+	var merged []Tool
+	index := make(map[string]int)
 	for _, tool := range tools {
-		sb.WriteString(fmt.Sprintf("%s = \"%s\"\n", tool.Name, tool.Version))
+		i, ok := index[tool.Name]
+		if !ok {
+			i = len(merged)
+			index[tool.Name] = i
+			merged = append(merged, Tool{Name: tool.Name})
+		}
+		merged[i].Specs = append(merged[i].Specs, ToolSpec{Version: tool.Version})
 	}
 
-	return sb.String()
+	for i := range merged {
+		old := specsOf[merged[i].Name]
+		for j := range merged[i].Specs {
+			spec := &merged[i].Specs[j]
+			for _, o := range old {
+				if o.Version == spec.Version {
+					spec.Options = o.Options
+					break
+				}
+			}
+			if spec.Options == nil && len(old) == 1 && len(merged[i].Specs) == 1 {
+				spec.Options = old[0].Options
+			}
+		}
+	}
+	return merged
 }
 
 // GenerateToolVersions generates .tool-versions content from installed tools
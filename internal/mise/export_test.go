@@ -5,72 +5,97 @@ import (
 	"testing"
 )
 
-func TestParseMiseLsOutput(t *testing.T) {
+func TestParseMiseLsJSON(t *testing.T) {
+	output := `{
+  "python": [
+    {
+      "version": "3.11.9",
+      "install_path": "/home/user/.local/share/mise/installs/python/3.11.9",
+      "installed": true,
+      "active": false
+    },
+    {
+      "version": "3.12.4",
+      "requested_version": "3.12",
+      "install_path": "/home/user/.local/share/mise/installs/python/3.12.4",
+      "source": {"type": "mise.toml", "path": "/home/user/.config/mise/config.toml"},
+      "installed": true,
+      "active": true
+    }
+  ],
+  "node": [
+    {
+      "version": "20.15.0",
+      "requested_version": "lts",
+      "source": {"type": ".tool-versions", "path": "/home/user/project/.tool-versions"},
+      "installed": false,
+      "active": false
+    }
+  ]
+}`
+	expected := []InstalledTool{
+		{Name: "node", Version: "20.15.0", Requested: "lts", Source: "/home/user/project/.tool-versions"},
+		{Name: "python", Version: "3.11.9", Installed: true},
+		{Name: "python", Version: "3.12.4", Requested: "3.12", Active: true, Installed: true, Source: "/home/user/.config/mise/config.toml"},
+	}
+
+	result, err := ParseMiseLsJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseMiseLsJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("ParseMiseLsJSON() = %+v, want %+v", result, expected)
+	}
+
+	if _, err := ParseMiseLsJSON([]byte("node 20.15.0")); err == nil {
+		t.Error("ParseMiseLsJSON() should return error for text output")
+	}
+}
+
+func TestExportVersions(t *testing.T) {
+	tools := []InstalledTool{
+		{Name: "node", Version: "20.14.0", Installed: true},
+		{Name: "node", Version: "20.15.0", Requested: "20", Active: true, Installed: true},
+		{Name: "node", Version: "22.3.0", Requested: "22", Installed: true},
+		{Name: "python", Version: "3.11.9", Installed: true},
+		{Name: "ruby", Version: "3.3.4", Requested: "3.3", Active: true},
+	}
+
 	tests := []struct {
 		name     string
-		input    string
-		expected []InstalledTool
+		opts     ExportOptions
+		expected []string
 	}{
 		{
-			name:     "empty output",
-			input:    "",
-			expected: nil,
-		},
-		{
-			name: "single tool",
-			input: `node  20.10.0  ~/.local/share/mise/installs/node/20.10.0`,
-			expected: []InstalledTool{
-				{Name: "node", Version: "20.10.0"},
-			},
-		},
-		{
-			name: "multiple tools",
-			input: `node    20.10.0   ~/.local/share/mise/installs/node/20.10.0
-python  3.12.0    ~/.local/share/mise/installs/python/3.12.0
-go      1.21.5    ~/.local/share/mise/installs/go/1.21.5`,
-			expected: []InstalledTool{
-				{Name: "node", Version: "20.10.0"},
-				{Name: "python", Version: "3.12.0"},
-				{Name: "go", Version: "1.21.5"},
-			},
+			name:     "requested",
+			opts:     ExportOptions{VersionMode: VersionRequested},
+			expected: []string{"node@20", "node@22", "ruby@3.3"},
 		},
 		{
-			name: "with set by info",
-			input: `node    20.10.0  (set by ~/.config/mise/config.toml)
-python  3.12.0   (set by ~/.tool-versions)`,
-			expected: []InstalledTool{
-				{Name: "node", Version: "20.10.0"},
-				{Name: "python", Version: "3.12.0"},
-			},
+			name:     "requested active only",
+			opts:     ExportOptions{VersionMode: VersionRequested, ActiveOnly: true},
+			expected: []string{"node@20", "ruby@3.3"},
 		},
 		{
-			name: "with empty lines",
-			input: `node  20.10.0  ~/.local/share/mise/installs/node/20.10.0
-
-python  3.12.0    ~/.local/share/mise/installs/python/3.12.0
-`,
-			expected: []InstalledTool{
-				{Name: "node", Version: "20.10.0"},
-				{Name: "python", Version: "3.12.0"},
-			},
+			name:     "exact",
+			opts:     ExportOptions{VersionMode: VersionExact},
+			expected: []string{"node@20.15.0", "node@22.3.0", "ruby@3.3.4"},
 		},
 		{
-			name: "duplicate versions filtered",
-			input: `node  20.10.0  ~/.local/share/mise/installs/node/20.10.0
-node  20.10.0  (set by ~/.config/mise/config.toml)
-python  3.12.0  ~/.local/share/mise/installs/python/3.12.0`,
-			expected: []InstalledTool{
-				{Name: "node", Version: "20.10.0"},
-				{Name: "python", Version: "3.12.0"},
-			},
+			name:     "exact active only",
+			opts:     ExportOptions{VersionMode: VersionExact, ActiveOnly: true},
+			expected: []string{"node@20.15.0", "ruby@3.3.4"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ParseMiseLsOutput(tt.input)
+			var result []string
+			for _, tool := range exportVersions(tools, tt.opts) {
+				result = append(result, tool.Name+"@"+tool.Version)
+			}
 			if !reflect.DeepEqual(result, tt.expected) {
-				t.Errorf("ParseMiseLsOutput() = %v, want %v", result, tt.expected)
+				t.Errorf("exportVersions() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -111,6 +136,19 @@ node = "20.10.0"
 node = "20.10.0"
 python = "3.12.0"
 go = "1.21.5"
+`,
+		},
+		{
+			name: "multiple versions and backend tools",
+			tools: []InstalledTool{
+				{Name: "python", Version: "3.12.0"},
+				{Name: "npm:prettier", Version: "3.3.3"},
+				{Name: "python", Version: "3.11.0"},
+			},
+			expected: `# Generated by goodbye export mise
+[tools]
+python = ["3.12.0", "3.11.0"]
+"npm:prettier" = "3.3.3"
 `,
 		},
 	}
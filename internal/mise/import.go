@@ -167,60 +167,26 @@ func Import(opts ImportOptions) error {
 	return nil
 }
 
-// ParseTOML parses a .mise.toml file and extracts tools
+// ParseTOML parses a .mise.toml file and extracts tools, one per version
 func ParseTOML(content string) ([]InstalledTool, error) {
-	var tools []InstalledTool
-	inToolsSection := false
-
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Check for section headers
-		if strings.HasPrefix(line, "[") {
-			inToolsSection = strings.HasPrefix(line, "[tools]")
-			continue
-		}
+	f, err := ParseConfigFile(content)
+	if err != nil {
+		return nil, err
+	}
 
-		// Parse tool entries in [tools] section
-		if inToolsSection {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				name := strings.TrimSpace(parts[0])
-				version := strings.TrimSpace(parts[1])
-				// Remove quotes
-				version = strings.Trim(version, `"'`)
-
-				// Handle array format: ["3.12", "3.11"]
-				if strings.HasPrefix(version, "[") {
-					version = strings.Trim(version, "[]")
-					versions := strings.Split(version, ",")
-					for _, v := range versions {
-						v = strings.TrimSpace(v)
-						v = strings.Trim(v, `"'`)
-						if v != "" {
-							tools = append(tools, InstalledTool{
-								Name:    name,
-								Version: v,
-							})
-						}
-					}
-				} else {
-					tools = append(tools, InstalledTool{
-						Name:    name,
-						Version: version,
-					})
-				}
+	var tools []InstalledTool
+	for _, tool := range f.Tools {
+		for _, spec := range tool.Specs {
+			if spec.Version == "" {
+				continue
 			}
+			tools = append(tools, InstalledTool{
+				Name:    tool.Name,
+				Version: spec.Version,
+			})
 		}
 	}
-
-	return tools, scanner.Err()
+	return tools, nil
 }
 
 // ParseToolVersions parses a .tool-versions file
@@ -96,6 +96,59 @@ python = ["3.12.0", "3.11.0"]`,
 			},
 			wantErr: false,
 		},
+		{
+			name: "inline table",
+			input: `[tools]
+node = { version = "20", postinstall = "corepack enable" }`,
+			expected: []InstalledTool{
+				{Name: "node", Version: "20"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "multi-line array",
+			input: `[tools]
+python = [
+  "3.12",
+  "3.11", # legacy projects
+]`,
+			expected: []InstalledTool{
+				{Name: "python", Version: "3.12"},
+				{Name: "python", Version: "3.11"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "quoted keys",
+			input: `[tools]
+"npm:prettier" = "3"
+"aqua:cli/cli" = "latest"`,
+			expected: []InstalledTool{
+				{Name: "npm:prettier", Version: "3"},
+				{Name: "aqua:cli/cli", Version: "latest"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tools subtable",
+			input: `[tools.node]
+version = "20"`,
+			expected: []InstalledTool{
+				{Name: "node", Version: "20"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid toml",
+			input:   "[tools\nnode = \"20\"",
+			wantErr: true,
+		},
+		{
+			name: "invalid tool value",
+			input: `[tools]
+node = true`,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,152 @@
+package mise
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const journalFile = "mise-journal.json"
+
+// Journal status values
+const (
+	JournalPending  = "pending"  // migration started but did not finish
+	JournalMigrated = "migrated" // migrated and uninstalled from brew
+	JournalRetained = "retained" // migrated, brew copy retained
+	JournalFailed   = "failed"
+	JournalReverted = "reverted" // rolled back with --rollback
+)
+
+// Journal records every brew -> mise migration so it can be rolled back
+type Journal struct {
+	Entries []*JournalEntry `json:"entries"`
+
+	path string
+}
+
+// JournalEntry represents the migration of a single tool
+type JournalEntry struct {
+	BrewName    string        `json:"brew_name"`
+	BrewVersion string        `json:"brew_version,omitempty"` // installed keg version before the migration
+	MiseName    string        `json:"mise_name"`
+	Version     string        `json:"version"` // version installed with mise
+	Status      string        `json:"status"`
+	Steps       []JournalStep `json:"steps"`
+	MigratedAt  time.Time     `json:"migrated_at"`
+	RevertedAt  *time.Time    `json:"reverted_at,omitempty"`
+}
+
+// JournalStep represents a command run for a migration or rollback
+type JournalStep struct {
+	Command string    `json:"command"`
+	Error   string    `json:"error,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// defaultJournalPath returns $XDG_STATE_HOME/goodbye/mise-journal.json,
+// falling back to ~/.local/state/goodbye/mise-journal.json
+func defaultJournalPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+	return filepath.Join(stateDir, "goodbye", journalFile), nil
+}
+
+// loadJournal reads a journal from path ("" for the default path),
+// returning an empty journal if it does not exist
+func loadJournal(path string) (*Journal, error) {
+	if path == "" {
+		var err error
+		if path, err = defaultJournalPath(); err != nil {
+			return nil, err
+		}
+	}
+
+	j := &Journal{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %w", path, err)
+	}
+	return j, nil
+}
+
+// start adds a pending entry for a candidate and saves the journal
+func (j *Journal) start(c MigrationCandidate, brewVersion string) (*JournalEntry, error) {
+	e := &JournalEntry{
+		BrewName:    c.BrewName,
+		BrewVersion: brewVersion,
+		MiseName:    c.MiseName,
+		Version:     c.Version,
+		Status:      JournalPending,
+		MigratedAt:  time.Now(),
+	}
+	j.Entries = append(j.Entries, e)
+	return e, j.save()
+}
+
+// run runs a command for an entry and records it as a step
+func (j *Journal) run(e *JournalEntry, cmdStr string, verbose bool) error {
+	runErr := runCommand(cmdStr, verbose)
+	step := JournalStep{Command: cmdStr, At: time.Now()}
+	if runErr != nil {
+		step.Error = runErr.Error()
+	}
+	e.Steps = append(e.Steps, step)
+	if err := j.save(); err != nil {
+		fmt.Printf("  Warning: failed to save journal: %v\n", err)
+	}
+	return runErr
+}
+
+// setStatus updates the status of an entry and saves the journal
+func (j *Journal) setStatus(e *JournalEntry, status string) {
+	e.Status = status
+	if status == JournalReverted {
+		now := time.Now()
+		e.RevertedAt = &now
+	}
+	if err := j.save(); err != nil {
+		fmt.Printf("  Warning: failed to save journal: %v\n", err)
+	}
+}
+
+// revertible returns the migrated entries that have not been rolled back,
+// limited to the tools in only if it is not empty
+func (j *Journal) revertible(only []string) []*JournalEntry {
+	var entries []*JournalEntry
+	for _, e := range j.Entries {
+		if e.Status != JournalMigrated && e.Status != JournalRetained {
+			continue
+		}
+		c := MigrationCandidate{BrewName: e.BrewName, NormalizedName: normalizeFormulaName(e.BrewName), MiseName: e.MiseName}
+		if len(only) > 0 && !matchesAny(c, only) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func (j *Journal) save() error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, append(data, '\n'), 0644)
+}
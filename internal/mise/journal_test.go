@@ -0,0 +1,281 @@
+package mise
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+func TestJournalRecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "goodbye", journalFile)
+
+	j, err := loadJournal(path)
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	e, err := j.start(MigrationCandidate{BrewName: "node", MiseName: "node", Version: "22"}, "22.3.0")
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if err := j.run(e, "true node@22", false); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if err := j.run(e, "false node@22", false); err == nil {
+		t.Fatal("run() should return the command error")
+	}
+	j.setStatus(e, JournalMigrated)
+
+	loaded, err := loadJournal(path)
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if len(loaded.Entries) != 1 {
+		t.Fatalf("Entries = %d, want 1", len(loaded.Entries))
+	}
+	got := loaded.Entries[0]
+	if got.BrewName != "node" || got.BrewVersion != "22.3.0" || got.Version != "22" || got.Status != JournalMigrated {
+		t.Errorf("entry = %+v", got)
+	}
+	if len(got.Steps) != 2 || got.Steps[0].Error != "" || got.Steps[1].Error == "" {
+		t.Errorf("steps = %+v, want a successful and a failed step", got.Steps)
+	}
+	if got.RevertedAt != nil {
+		t.Error("RevertedAt should be unset before rollback")
+	}
+}
+
+func TestDefaultJournalPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	path, err := defaultJournalPath()
+	if err != nil {
+		t.Fatalf("defaultJournalPath() error = %v", err)
+	}
+	if path != "/tmp/state/goodbye/mise-journal.json" {
+		t.Errorf("defaultJournalPath() = %q", path)
+	}
+}
+
+func TestLoadJournalInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journalFile)
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+	if _, err := loadJournal(path); err == nil {
+		t.Error("loadJournal() should return error for invalid JSON")
+	}
+}
+
+func TestJournalRevertible(t *testing.T) {
+	j := &Journal{Entries: []*JournalEntry{
+		{BrewName: "node", MiseName: "node", Status: JournalMigrated},
+		{BrewName: "python@3.12", MiseName: "python", Status: JournalRetained},
+		{BrewName: "go", MiseName: "go", Status: JournalFailed},
+		{BrewName: "ruby", MiseName: "ruby", Status: JournalReverted},
+	}}
+
+	names := func(entries []*JournalEntry) []string {
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.BrewName)
+		}
+		return result
+	}
+
+	if result := names(j.revertible(nil)); !reflect.DeepEqual(result, []string{"node", "python@3.12"}) {
+		t.Errorf("revertible() = %v, want [node python@3.12]", result)
+	}
+	if result := names(j.revertible([]string{"python"})); !reflect.DeepEqual(result, []string{"python@3.12"}) {
+		t.Errorf("revertible(python) = %v, want [python@3.12]", result)
+	}
+}
+
+func TestRollbackSteps(t *testing.T) {
+	cmds := commandsFor(config.DefaultConfig())
+
+	migrated := &JournalEntry{BrewName: "node", MiseName: "node", Version: "22", Status: JournalMigrated}
+	expected := []string{"brew install node", "mise unuse -g node@22", "mise uninstall node@22"}
+	if result := rollbackSteps(migrated, "node", cmds); !reflect.DeepEqual(result, expected) {
+		t.Errorf("rollbackSteps() = %v, want %v", result, expected)
+	}
+
+	// The brew copy of a retained entry is still installed
+	retained := &JournalEntry{BrewName: "python@3.12", MiseName: "python", Version: "3.12", Status: JournalRetained}
+	expected = []string{"mise unuse -g python@3.12", "mise uninstall python@3.12"}
+	if result := rollbackSteps(retained, "python@3.12", cmds); !reflect.DeepEqual(result, expected) {
+		t.Errorf("rollbackSteps() = %v, want %v", result, expected)
+	}
+}
+
+func TestRollbackEntry(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewInstallCmd = "true %s"
+	cfg.Mise.Commands.UnuseGlobalCmd = "true %s"
+	cfg.Mise.Commands.UninstallCmd = "true %s"
+	cfg.Mise.Commands.BrewInfoCmd = fakeBrewInfo(map[string]string{"node": "22.4.1"})
+
+	j, err := loadJournal(filepath.Join(t.TempDir(), journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	e, _ := j.start(MigrationCandidate{BrewName: "node", MiseName: "node", Version: "22"}, "22.3.0")
+	j.setStatus(e, JournalMigrated)
+
+	if err := rollbackEntry(cfg, j, e, commandsFor(cfg), false); err != nil {
+		t.Fatalf("rollbackEntry() error = %v", err)
+	}
+	if e.Status != JournalReverted || e.RevertedAt == nil {
+		t.Errorf("entry = %+v, want reverted", e)
+	}
+	if len(e.Steps) != 3 {
+		t.Errorf("steps = %d, want 3", len(e.Steps))
+	}
+}
+
+// fakeBrewInfo returns a brew info command that knows the given
+// formulas and their stable versions
+func fakeBrewInfo(formulas map[string]string) string {
+	var cases []string
+	for name, version := range formulas {
+		cases = append(cases, fmt.Sprintf(`%s) echo "{\"formulae\":[{\"versions\":{\"stable\":\"%s\"}}]}";;`, name, version))
+	}
+	sort.Strings(cases)
+	return `sh -c 'case "$1" in ` + strings.Join(cases, " ") + ` *) exit 1;; esac' _ %s`
+}
+
+func TestRollbackFormula(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewInfoCmd = fakeBrewInfo(map[string]string{
+		"node":        "23.1.0",
+		"node@22":     "22.11.0",
+		"python":      "3.13.0",
+		"python@3.12": "3.12.7",
+		"go":          "1.23.2",
+		"ruby":        "3.3.5",
+	})
+
+	tests := []struct {
+		name     string
+		entry    JournalEntry
+		expected string
+		wantErr  bool
+	}{
+		{"same major version", JournalEntry{BrewName: "go", BrewVersion: "1.22.5"}, "go", false},
+		{"same minor version", JournalEntry{BrewName: "node", BrewVersion: "23.0.0"}, "node", false},
+		{"versioned formula by major", JournalEntry{BrewName: "node", BrewVersion: "22.3.0"}, "node@22", false},
+		{"versioned formula by minor", JournalEntry{BrewName: "python", BrewVersion: "3.12.4"}, "python@3.12", false},
+		{"no versioned formula", JournalEntry{BrewName: "ruby", BrewVersion: "2.7.8"}, "", true},
+		{"unknown formula", JournalEntry{BrewName: "deno", BrewVersion: "1.46.0"}, "", true},
+		{"already versioned", JournalEntry{BrewName: "python@3.11", BrewVersion: "3.11.9"}, "python@3.11", false},
+		{"no recorded version", JournalEntry{BrewName: "ruby"}, "ruby", false},
+		{"brew copy retained", JournalEntry{BrewName: "ruby", BrewVersion: "2.7.8", Status: JournalRetained}, "ruby", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := rollbackFormula(cfg, &tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rollbackFormula() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("rollbackFormula() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRollbackEntryInstallsVersionedFormula(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewInstallCmd = "true %s"
+	cfg.Mise.Commands.UnuseGlobalCmd = "true %s"
+	cfg.Mise.Commands.UninstallCmd = "true %s"
+	cfg.Mise.Commands.BrewInfoCmd = fakeBrewInfo(map[string]string{"node": "23.1.0", "node@22": "22.11.0"})
+
+	j, err := loadJournal(filepath.Join(t.TempDir(), journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	e, _ := j.start(MigrationCandidate{BrewName: "node", MiseName: "node", Version: "22"}, "22.3.0")
+	j.setStatus(e, JournalMigrated)
+
+	if err := rollbackEntry(cfg, j, e, commandsFor(cfg), false); err != nil {
+		t.Fatalf("rollbackEntry() error = %v", err)
+	}
+	if e.Status != JournalReverted || len(e.Steps) != 3 || e.Steps[0].Command != "true node@22" {
+		t.Errorf("entry = %+v, want reverted after installing node@22", e)
+	}
+}
+
+func TestRollbackEntryFailsWithoutRecordedVersion(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewInstallCmd = "true %s"
+	cfg.Mise.Commands.UnuseGlobalCmd = "true %s"
+	cfg.Mise.Commands.UninstallCmd = "true %s"
+	cfg.Mise.Commands.BrewInfoCmd = fakeBrewInfo(map[string]string{"ruby": "3.3.5"})
+
+	j, err := loadJournal(filepath.Join(t.TempDir(), journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	e, _ := j.start(MigrationCandidate{BrewName: "ruby", MiseName: "ruby", Version: "2.7"}, "2.7.8")
+	j.setStatus(e, JournalMigrated)
+
+	if err := rollbackEntry(cfg, j, e, commandsFor(cfg), false); err == nil {
+		t.Fatal("rollbackEntry() should fail when brew cannot install the recorded version")
+	}
+	// Nothing ran, so the mise copy is still in use and the entry can be retried
+	if e.Status != JournalMigrated || len(e.Steps) != 0 {
+		t.Errorf("entry = %+v, want still migrated with no steps", e)
+	}
+}
+
+func TestRollbackEntryStopsWhenBrewInstallFails(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewInstallCmd = "false %s"
+	cfg.Mise.Commands.UnuseGlobalCmd = "true %s"
+	cfg.Mise.Commands.UninstallCmd = "true %s"
+
+	j, err := loadJournal(filepath.Join(t.TempDir(), journalFile))
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	e, _ := j.start(MigrationCandidate{BrewName: "node", MiseName: "node", Version: "22"}, "")
+	j.setStatus(e, JournalMigrated)
+
+	if err := rollbackEntry(cfg, j, e, commandsFor(cfg), false); err == nil {
+		t.Fatal("rollbackEntry() should fail when brew install fails")
+	}
+	if e.Status != JournalMigrated || len(e.Steps) != 1 {
+		t.Errorf("entry = %+v, want still migrated with only the brew step", e)
+	}
+}
+
+func TestRollbackDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), journalFile)
+	j, err := loadJournal(path)
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	e, _ := j.start(MigrationCandidate{BrewName: "node", MiseName: "node", Version: "22"}, "22.3.0")
+	j.setStatus(e, JournalMigrated)
+
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewInstallCmd = "false %s"
+	cfg.Mise.Commands.BrewInfoCmd = fakeBrewInfo(map[string]string{"node": "22.4.1"})
+	if err := Rollback(cfg, RollbackOptions{DryRun: true, JournalPath: path}); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	loaded, err := loadJournal(path)
+	if err != nil {
+		t.Fatalf("loadJournal() error = %v", err)
+	}
+	if loaded.Entries[0].Status != JournalMigrated || len(loaded.Entries[0].Steps) != 0 {
+		t.Errorf("dry-run should not change the journal: %+v", loaded.Entries[0])
+	}
+}
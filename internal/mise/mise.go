@@ -6,34 +6,71 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
 	"strings"
 
+	"github.com/yyYank/goodbye/internal/brew"
 	"github.com/yyYank/goodbye/internal/config"
 )
 
 // MigrateOptions represents options for the brew --mise command
 type MigrateOptions struct {
-	DryRun  bool
-	Verbose bool
+	DryRun          bool
+	Verbose         bool
+	VersionStrategy string   // latest, keep-major or keep-exact ("" uses the config)
+	Only            []string // migrate only these tools (brew, normalized or mise names)
+	Exclude         []string // never migrate these tools, in addition to [mise] exclude
+	Interactive     bool     // ask for each candidate instead of once for all
+	JournalPath     string   // migration journal ("" for ~/.local/state/goodbye/mise-journal.json)
 }
 
+// Version strategies for the mise migration
+const (
+	VersionLatest    = "latest"     // always install the latest version
+	VersionKeepMajor = "keep-major" // keep the versioned formula series (@3.11) or the keg's major version
+	VersionKeepExact = "keep-exact" // keep the installed keg version
+)
+
 // RegistryEntry represents an entry from mise registry
 type RegistryEntry struct {
-	Short   string   `json:"short"`
-	Full    string   `json:"full"`
-	Aliases []string `json:"aliases,omitempty"`
+	Short    string   `json:"short"`
+	Full     string   `json:"full"`               // space-separated backends in older mise
+	Backends []string `json:"backends,omitempty"` // backends in newer mise
+	Aliases  []string `json:"aliases,omitempty"`
 }
 
+// Reasons a formula matched a registry entry
+const (
+	MatchMapping = "known mapping"
+	MatchShort   = "short name"
+	MatchAlias   = "alias"
+	MatchRepo    = "backend repo"
+	MatchBackend = "mapped backend" // known mapping to a backend-qualified spec, not checked against the registry
+)
+
 // MigrationCandidate represents a tool that can be migrated
 type MigrationCandidate struct {
-	BrewName      string
+	BrewName       string
 	NormalizedName string
-	MiseName      string
+	MiseName       string
+	Backend        string   // backend mise resolves the tool with (e.g., aqua:BurntSushi/ripgrep)
+	Reason         string   // why the formula matched (see Match* constants)
+	Version        string   // version or version prefix to install (e.g., 3.11, latest)
+	Dependents     []string // installed brew formulas that depend on the formula
+	KeepBrew       bool     // keep the brew copy after migrating (it has dependents or they could not be checked)
+	PinnedVersion  string   // version from the known mapping, overriding the version strategy
+	NoUninstall    bool     // the known mapping sets uninstall_brew = false
+	SmokeTest      string   // smoke test command from the known mapping
 }
 
 // Migrate performs the brew to mise migration
 func Migrate(cfg *config.Config, opts MigrateOptions) error {
+	strategy, err := versionStrategy(cfg, opts)
+	if err != nil {
+		return err
+	}
+
 	// Step 1: Get Homebrew formula list
 	fmt.Println("Getting Homebrew formula list...")
 	formulas, err := getBrewFormulas(cfg)
@@ -42,102 +79,167 @@ func Migrate(cfg *config.Config, opts MigrateOptions) error {
 	}
 	fmt.Printf("Found %d formulas\n", len(formulas))
 
-	// Step 2: Get mise registry
+	// Step 2: Get mise registry (JSON on newer mise, text otherwise)
 	fmt.Println("Getting mise registry...")
-	registry, err := getMiseRegistry(cfg)
+	registry, err := getMiseRegistryJSON(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get mise registry: %w", err)
+		if opts.Verbose {
+			fmt.Printf("  JSON registry unavailable (%v), falling back to text output\n", err)
+		}
+		registry, err = getMiseRegistry(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get mise registry: %w", err)
+		}
 	}
 	fmt.Printf("Found %d tools in mise registry\n", len(registry))
 
 	// Step 3: Find migration candidates
 	candidates := findCandidates(formulas, registry, cfg)
+
+	// Apply --only, --exclude and the exclude list in config
+	exclude := append(append([]string{}, cfg.Mise.Exclude...), opts.Exclude...)
+	candidates, unknown := filterCandidates(candidates, opts.Only, exclude)
+	for _, name := range unknown {
+		fmt.Printf("Warning: %s is not a migration candidate\n", name)
+	}
+
+	// Step 4: Resolve the version to install for each candidate
+	var resolved, unresolved []MigrationCandidate
+	for _, c := range candidates {
+		if c.PinnedVersion != "" {
+			c.Version = c.PinnedVersion
+			resolved = append(resolved, c)
+			continue
+		}
+		version, err := resolveVersion(cfg, c.BrewName, strategy)
+		if err != nil {
+			if opts.Verbose {
+				fmt.Printf("  Could not resolve version for %s: %v\n", c.BrewName, err)
+			}
+			unresolved = append(unresolved, c)
+			continue
+		}
+		c.Version = version
+		resolved = append(resolved, c)
+	}
+	candidates = resolved
+	if len(unresolved) > 0 {
+		fmt.Printf("Skipping %d candidates without a resolvable version (%s strategy)\n", len(unresolved), strategy)
+	}
+
 	if len(candidates) == 0 {
 		fmt.Println("\nNo migration candidates found.")
 		return nil
 	}
 
+	// Step 5: Check brew formulas that still depend on each candidate
+	for i := range candidates {
+		c := &candidates[i]
+		if c.NoUninstall {
+			c.KeepBrew = true
+			continue
+		}
+		dependents, err := brewDependents(cfg, c.BrewName)
+		if err != nil {
+			fmt.Printf("  Warning: could not check dependents of %s, keeping the brew copy: %v\n", c.BrewName, err)
+			c.KeepBrew = true
+			continue
+		}
+		c.Dependents = dependents
+		c.KeepBrew = len(dependents) > 0
+	}
+
 	fmt.Printf("\nFound %d migration candidates:\n", len(candidates))
-	fmt.Println(strings.Repeat("-", 60))
-	fmt.Printf("%-25s %-20s %s\n", "BREW", "NORMALIZED", "MISE")
-	fmt.Println(strings.Repeat("-", 60))
+	fmt.Println(strings.Repeat("-", 150))
+	fmt.Printf("%-25s %-15s %-15s %-10s %-28s %-15s %-28s %s\n", "BREW", "NORMALIZED", "MISE", "VERSION", "BACKEND", "MATCH", "BREW DEPENDENTS", "MAPPING OPTIONS")
+	fmt.Println(strings.Repeat("-", 150))
 	for _, c := range candidates {
-		fmt.Printf("%-25s %-20s %s\n", c.BrewName, c.NormalizedName, c.MiseName)
+		fmt.Printf("%-25s %-15s %-15s %-10s %-28s %-15s %-28s %s\n", c.BrewName, c.NormalizedName, c.MiseName, c.Version, c.Backend, c.Reason, dependentsSummary(c), mappingOptions(c))
 	}
-	fmt.Println(strings.Repeat("-", 60))
+	fmt.Println(strings.Repeat("-", 150))
+
+	cmds := commandsFor(cfg)
 
 	if opts.DryRun {
-		fmt.Println("\n[dry-run] Would perform the following actions:")
+		fmt.Printf("\n[dry-run] Would perform the following actions (%s strategy):\n", strategy)
 		for _, c := range candidates {
-			fmt.Printf("  1. mise install %s@latest\n", c.MiseName)
-			fmt.Printf("  2. mise use -g %s@latest\n", c.MiseName)
-			fmt.Printf("  3. Verify installation\n")
-			fmt.Printf("  4. brew uninstall %s\n", c.BrewName)
+			fmt.Printf("  1. %s\n", toolCommand(cmds.Install, c.MiseName, c.Version))
+			fmt.Printf("  2. %s\n", toolCommand(cmds.UseGlobal, c.MiseName, c.Version))
+			if test, ok := smokeTestFor(cfg, c); ok {
+				fmt.Printf("  3. Verify installation and run smoke test: %s\n", test.Command)
+			} else {
+				fmt.Printf("  3. Verify installation\n")
+			}
+			if c.KeepBrew {
+				fmt.Printf("  4. Keep the brew copy of %s (%s)\n", c.BrewName, dependentsSummary(c))
+			} else {
+				fmt.Printf("  4. %s\n", fmt.Sprintf(cmds.BrewUninstall, c.BrewName))
+			}
 			fmt.Println()
 		}
 		fmt.Println("\nTo apply these changes, run with --apply")
+		if opts.Interactive {
+			fmt.Println("With --apply, --interactive asks for each candidate.")
+		}
 		return nil
 	}
 
-	// Step 4: Confirm
-	fmt.Print("\nDo you want to proceed with migration? [y/N]: ")
-	var response string
-	fmt.Scanln(&response)
-	if strings.ToLower(response) != "y" {
-		fmt.Println("Migration cancelled.")
-		return nil
+	// Step 6: Confirm, for all candidates or one by one
+	if opts.Interactive {
+		fmt.Println()
+		selected, skipForever := promptCandidates(candidates, os.Stdin, os.Stdout)
+		if len(skipForever) > 0 {
+			cfg.Mise.Exclude = addExcludes(cfg.Mise.Exclude, skipForever)
+			if err := config.SaveMiseExclude(cfg.Mise.Exclude); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			fmt.Println("Exclude list saved to ~/.goodbye.toml")
+		}
+		candidates = selected
+		if len(candidates) == 0 {
+			fmt.Println("No candidates selected.")
+			return nil
+		}
+	} else {
+		fmt.Print("\nDo you want to proceed with migration? [y/N]: ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" {
+			fmt.Println("Migration cancelled.")
+			return nil
+		}
 	}
 
-	// Step 5-8: Migrate each candidate
-	var succeeded, failed []MigrationCandidate
-	for _, c := range candidates {
-		fmt.Printf("\nMigrating %s -> %s\n", c.BrewName, c.MiseName)
+	// Every step is recorded so the migration can be rolled back
+	journal, err := loadJournal(opts.JournalPath)
+	if err != nil {
+		return fmt.Errorf("failed to load migration journal: %w", err)
+	}
 
-		// Install with mise
-		fmt.Printf("  Installing %s with mise...\n", c.MiseName)
-		installCmd := cfg.Mise.Commands.InstallCmd
-		if installCmd == "" {
-			installCmd = "mise install %s@latest"
-		}
-		if err := runCommand(fmt.Sprintf(installCmd, c.MiseName), opts.Verbose); err != nil {
-			fmt.Printf("  Failed to install: %v\n", err)
-			failed = append(failed, c)
-			continue
-		}
+	// Step 7-10: Migrate each candidate
+	var succeeded, retained, failed []MigrationCandidate
+	reasons := make(map[string]string)
+	for _, c := range candidates {
+		fmt.Printf("\nMigrating %s -> %s@%s\n", c.BrewName, c.MiseName, c.Version)
 
-		// Set global
-		fmt.Printf("  Setting %s as global...\n", c.MiseName)
-		useGlobalCmd := cfg.Mise.Commands.UseGlobalCmd
-		if useGlobalCmd == "" {
-			useGlobalCmd = "mise use -g %s@latest"
-		}
-		if err := runCommand(fmt.Sprintf(useGlobalCmd, c.MiseName), opts.Verbose); err != nil {
-			fmt.Printf("  Failed to set global: %v\n", err)
-			failed = append(failed, c)
-			continue
+		brewVersion, _ := brewKegVersion(cfg, c.BrewName)
+		entry, err := journal.start(c, brewVersion)
+		if err != nil {
+			return fmt.Errorf("failed to write migration journal: %w", err)
 		}
 
-		// Verify installation
-		fmt.Printf("  Verifying installation...\n")
-		if err := verifyInstallation(cfg, c.MiseName); err != nil {
-			fmt.Printf("  Verification failed: %v\n", err)
+		status, reason := migrateCandidate(cfg, c, cmds, journal, entry, opts)
+		journal.setStatus(entry, status)
+		switch status {
+		case JournalMigrated:
+			succeeded = append(succeeded, c)
+			reasons[c.BrewName] = reason
+		case JournalRetained:
+			retained = append(retained, c)
+			reasons[c.BrewName] = reason
+		default:
 			failed = append(failed, c)
-			continue
-		}
-
-		// Uninstall from brew
-		fmt.Printf("  Uninstalling %s from brew...\n", c.BrewName)
-		brewUninstallCmd := cfg.Mise.Commands.BrewUninstallCmd
-		if brewUninstallCmd == "" {
-			brewUninstallCmd = "brew uninstall %s"
-		}
-		if err := runCommand(fmt.Sprintf(brewUninstallCmd, c.BrewName), opts.Verbose); err != nil {
-			fmt.Printf("  Warning: Failed to uninstall from brew: %v\n", err)
-			// Still consider it a success since mise is working
 		}
-
-		fmt.Printf("  Successfully migrated %s!\n", c.BrewName)
-		succeeded = append(succeeded, c)
 	}
 
 	// Summary
@@ -146,7 +248,17 @@ func Migrate(cfg *config.Config, opts MigrateOptions) error {
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Printf("Succeeded: %d\n", len(succeeded))
 	for _, c := range succeeded {
-		fmt.Printf("  - %s -> %s\n", c.BrewName, c.MiseName)
+		if reason := reasons[c.BrewName]; reason != "" {
+			fmt.Printf("  - %s -> %s@%s (%s)\n", c.BrewName, c.MiseName, c.Version, reason)
+			continue
+		}
+		fmt.Printf("  - %s -> %s@%s\n", c.BrewName, c.MiseName, c.Version)
+	}
+	if len(retained) > 0 {
+		fmt.Printf("Migrated but brew copy retained: %d\n", len(retained))
+		for _, c := range retained {
+			fmt.Printf("  - %s -> %s@%s (%s)\n", c.BrewName, c.MiseName, c.Version, reasons[c.BrewName])
+		}
 	}
 	if len(failed) > 0 {
 		fmt.Printf("Failed: %d\n", len(failed))
@@ -154,10 +266,115 @@ func Migrate(cfg *config.Config, opts MigrateOptions) error {
 			fmt.Printf("  - %s\n", c.BrewName)
 		}
 	}
+	fmt.Printf("\nMigration journal: %s\n", journal.path)
+	fmt.Println("Run 'goodbye brew --mise --rollback' to undo these migrations.")
 
 	return nil
 }
 
+// migrateCandidate installs a candidate with mise, verifies it and removes
+// the brew copy, recording each command in the journal. It returns the
+// resulting journal status and, for retained brew copies and binaries still
+// shadowed by Homebrew, the reason.
+func migrateCandidate(cfg *config.Config, c MigrationCandidate, cmds migrationCommands, journal *Journal, entry *JournalEntry, opts MigrateOptions) (string, string) {
+	// Install with mise
+	fmt.Printf("  Installing %s@%s with mise...\n", c.MiseName, c.Version)
+	if err := journal.run(entry, toolCommand(cmds.Install, c.MiseName, c.Version), opts.Verbose); err != nil {
+		fmt.Printf("  Failed to install: %v\n", err)
+		return JournalFailed, ""
+	}
+
+	// Set global
+	fmt.Printf("  Setting %s as global...\n", c.MiseName)
+	if err := journal.run(entry, toolCommand(cmds.UseGlobal, c.MiseName, c.Version), opts.Verbose); err != nil {
+		fmt.Printf("  Failed to set global: %v\n", err)
+		return JournalFailed, ""
+	}
+
+	// Verify installation
+	fmt.Printf("  Verifying installation...\n")
+	if err := verifyInstallation(cfg, c.MiseName); err != nil {
+		fmt.Printf("  Verification failed: %v\n", err)
+		return JournalFailed, ""
+	}
+
+	// Smoke test; the brew copy is kept if it fails
+	if test, ok := smokeTestFor(cfg, c); ok {
+		fmt.Printf("  Running smoke test: %s\n", test.Command)
+		if err := runSmokeTest(cfg, test, c.MiseName+"@"+c.Version); err != nil {
+			fmt.Printf("  Smoke test failed, keeping the brew copy of %s: %v\n", c.BrewName, err)
+			return JournalRetained, "smoke test failed"
+		}
+	}
+
+	// Keep brew copies that other formulas still depend on
+	if c.KeepBrew {
+		fmt.Printf("  Keeping the brew copy of %s (%s)\n", c.BrewName, dependentsSummary(c))
+		fmt.Printf("  Migrated %s, brew copy retained\n", c.BrewName)
+		return JournalRetained, dependentsSummary(c)
+	}
+
+	// Uninstall from brew
+	fmt.Printf("  Uninstalling %s from brew...\n", c.BrewName)
+	if err := journal.run(entry, fmt.Sprintf(cmds.BrewUninstall, c.BrewName), opts.Verbose); err != nil {
+		fmt.Printf("  Warning: Failed to uninstall from brew: %v\n", err)
+		// Still consider it a success since mise is working
+	}
+
+	// Another formula may still provide the binary ahead of mise on PATH
+	if test, ok := smokeTestFor(cfg, c); ok {
+		if err := checkShadowed(smokeBinary(test)); err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			fmt.Printf("  Migrated %s, but mise's copy is shadowed\n", c.BrewName)
+			return JournalMigrated, "shadowed by Homebrew"
+		}
+	}
+
+	fmt.Printf("  Successfully migrated %s!\n", c.BrewName)
+	return JournalMigrated, ""
+}
+
+// migrationCommands holds the command templates of the migration and rollback
+type migrationCommands struct {
+	Install       string // mise install, takes tool@version
+	UseGlobal     string // mise use -g, takes tool@version
+	Uninstall     string // mise uninstall, takes tool@version
+	UnuseGlobal   string // mise unuse -g, takes tool@version
+	BrewInstall   string // takes the formula name
+	BrewUninstall string // takes the formula name
+}
+
+// commandsFor returns the command templates from config, with defaults
+func commandsFor(cfg *config.Config) migrationCommands {
+	cmds := migrationCommands{
+		Install:       cfg.Mise.Commands.InstallCmd,
+		UseGlobal:     cfg.Mise.Commands.UseGlobalCmd,
+		Uninstall:     cfg.Mise.Commands.UninstallCmd,
+		UnuseGlobal:   cfg.Mise.Commands.UnuseGlobalCmd,
+		BrewInstall:   cfg.Mise.Commands.BrewInstallCmd,
+		BrewUninstall: cfg.Mise.Commands.BrewUninstallCmd,
+	}
+	if cmds.Install == "" {
+		cmds.Install = "mise install %s"
+	}
+	if cmds.UseGlobal == "" {
+		cmds.UseGlobal = "mise use -g %s"
+	}
+	if cmds.Uninstall == "" {
+		cmds.Uninstall = "mise uninstall %s"
+	}
+	if cmds.UnuseGlobal == "" {
+		cmds.UnuseGlobal = "mise unuse -g %s"
+	}
+	if cmds.BrewInstall == "" {
+		cmds.BrewInstall = "brew install %s"
+	}
+	if cmds.BrewUninstall == "" {
+		cmds.BrewUninstall = "brew uninstall %s"
+	}
+	return cmds
+}
+
 func getBrewFormulas(cfg *config.Config) ([]string, error) {
 	// Use command from config, fallback to default
 	cmdStr := cfg.Brew.Export.FormulaCmd
@@ -182,7 +399,7 @@ func getBrewFormulas(cfg *config.Config) ([]string, error) {
 	return formulas, scanner.Err()
 }
 
-func getMiseRegistry(cfg *config.Config) (map[string]string, error) {
+func getMiseRegistry(cfg *config.Config) ([]RegistryEntry, error) {
 	// Use command from config, fallback to default
 	cmdStr := cfg.Mise.Commands.RegistryCmd
 	if cmdStr == "" {
@@ -195,7 +412,7 @@ func getMiseRegistry(cfg *config.Config) (map[string]string, error) {
 		return nil, fmt.Errorf("mise command failed (is mise installed?): %w", err)
 	}
 
-	registry := make(map[string]string)
+	var registry []RegistryEntry
 	scanner := bufio.NewScanner(strings.NewReader(string(output)))
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -203,11 +420,13 @@ func getMiseRegistry(cfg *config.Config) (map[string]string, error) {
 			continue
 		}
 
-		// Parse registry output (format: "name  backend:path")
+		// Parse registry output (format: "name  backend:path [backend:path...]")
 		parts := strings.Fields(line)
 		if len(parts) >= 1 {
-			name := parts[0]
-			registry[strings.ToLower(name)] = name
+			registry = append(registry, RegistryEntry{
+				Short: parts[0],
+				Full:  strings.Join(parts[1:], " "),
+			})
 		}
 	}
 	return registry, scanner.Err()
@@ -234,6 +453,193 @@ func getMiseRegistryJSON(cfg *config.Config) ([]RegistryEntry, error) {
 	return entries, nil
 }
 
+// backends returns the backend specs of the entry, preferred first
+func (e RegistryEntry) backends() []string {
+	backends := append([]string{}, e.Backends...)
+	return append(backends, strings.Fields(e.Full)...)
+}
+
+// backendRepoName returns the tool name in a backend spec
+// (e.g., aqua:BurntSushi/ripgrep -> ripgrep, ubi:sharkdp/fd[exe=fd] -> fd)
+func backendRepoName(spec string) string {
+	if i := strings.Index(spec, "["); i >= 0 {
+		spec = spec[:i]
+	}
+	if i := strings.Index(spec, ":"); i >= 0 {
+		spec = spec[i+1:]
+	}
+	if spec == "" {
+		return ""
+	}
+	return strings.ToLower(path.Base(spec))
+}
+
+// registryMatch represents a registry entry found for a name
+type registryMatch struct {
+	Entry   RegistryEntry
+	Backend string
+	Reason  string
+}
+
+// indexRegistry indexes registry entries by short name, aliases and the
+// repo part of each backend. When names collide, short names win over
+// aliases and aliases win over repo names.
+func indexRegistry(registry []RegistryEntry) map[string]registryMatch {
+	index := make(map[string]registryMatch)
+	add := func(name string, m registryMatch) {
+		name = strings.ToLower(name)
+		if _, exists := index[name]; !exists && name != "" {
+			index[name] = m
+		}
+	}
+
+	preferred := func(e RegistryEntry) string {
+		if backends := e.backends(); len(backends) > 0 {
+			return backends[0]
+		}
+		return ""
+	}
+	for _, e := range registry {
+		add(e.Short, registryMatch{Entry: e, Backend: preferred(e), Reason: MatchShort})
+	}
+	for _, e := range registry {
+		for _, alias := range e.Aliases {
+			add(alias, registryMatch{Entry: e, Backend: preferred(e), Reason: MatchAlias})
+		}
+	}
+	for _, e := range registry {
+		for _, backend := range e.backends() {
+			add(backendRepoName(backend), registryMatch{Entry: e, Backend: backend, Reason: MatchRepo})
+		}
+	}
+	return index
+}
+
+// versionStrategy returns the version strategy from the options or config
+func versionStrategy(cfg *config.Config, opts MigrateOptions) (string, error) {
+	strategy := opts.VersionStrategy
+	if strategy == "" {
+		strategy = cfg.Mise.VersionStrategy
+	}
+	if strategy == "" {
+		strategy = VersionKeepMajor
+	}
+	switch strategy {
+	case VersionLatest, VersionKeepMajor, VersionKeepExact:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid version strategy %q (must be latest, keep-major or keep-exact)", strategy)
+}
+
+// resolveVersion returns the version to install for a formula.
+// keep-major uses the versioned formula suffix (python@3.11 -> 3.11) or the
+// major version of the installed keg; keep-exact uses the installed keg version.
+func resolveVersion(cfg *config.Config, formula, strategy string) (string, error) {
+	switch strategy {
+	case VersionLatest:
+		return "latest", nil
+	case VersionKeepMajor:
+		if version := formulaVersionSuffix(formula); version != "" {
+			return version, nil
+		}
+		version, err := brewKegVersion(cfg, formula)
+		if err != nil {
+			return "", err
+		}
+		major, _, _ := strings.Cut(version, ".")
+		return major, nil
+	default:
+		return brewKegVersion(cfg, formula)
+	}
+}
+
+// formulaVersionSuffix returns the version of a versioned formula
+// (e.g., python@3.11 -> 3.11), or "" if the formula is not versioned
+func formulaVersionSuffix(formula string) string {
+	re := regexp.MustCompile(`@([\d.]+)$`)
+	if m := re.FindStringSubmatch(formula); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// brewKegVersion returns the newest installed keg version of a formula
+// without the brew revision suffix
+func brewKegVersion(cfg *config.Config, formula string) (string, error) {
+	cmdTemplate := cfg.Mise.Commands.BrewVersionsCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "brew list --versions %s"
+	}
+
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(cmdTemplate, formula))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return brew.NewestKegVersion(string(output))
+}
+
+// brewDependents returns the installed formulas that depend on a formula
+func brewDependents(cfg *config.Config, formula string) ([]string, error) {
+	cmdTemplate := cfg.Mise.Commands.BrewUsesCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "brew uses --installed %s"
+	}
+
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(cmdTemplate, formula))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// dependentsSummary describes why a candidate's brew copy is kept, or "-"
+func dependentsSummary(c MigrationCandidate) string {
+	switch {
+	case c.NoUninstall:
+		return "uninstall_brew = false"
+	case len(c.Dependents) > 3:
+		return "required by " + strings.Join(c.Dependents[:3], ", ") + fmt.Sprintf(" and %d more", len(c.Dependents)-3)
+	case len(c.Dependents) > 0:
+		return "required by " + strings.Join(c.Dependents, ", ")
+	case c.KeepBrew:
+		return "dependents unknown"
+	}
+	return "-"
+}
+
+// isBackendSpec reports whether a mise tool is backend-qualified (e.g., aqua:cli/cli)
+func isBackendSpec(tool string) bool {
+	return strings.Contains(tool, ":")
+}
+
+// mappingOptions describes the known mapping options of a candidate, or "-"
+func mappingOptions(c MigrationCandidate) string {
+	var options []string
+	if c.PinnedVersion != "" {
+		options = append(options, "version="+c.PinnedVersion)
+	}
+	if c.NoUninstall {
+		options = append(options, "uninstall_brew=false")
+	}
+	if c.SmokeTest != "" {
+		options = append(options, "smoke_test="+c.SmokeTest)
+	}
+	if len(options) == 0 {
+		return "-"
+	}
+	return strings.Join(options, ", ")
+}
+
+// toolCommand fills a mise command template with tool@version.
+// Templates written for the former "%s@latest" form get the version too.
+func toolCommand(cmdTemplate, tool, version string) string {
+	cmdTemplate = strings.Replace(cmdTemplate, "%s@latest", "%s", 1)
+	return fmt.Sprintf(cmdTemplate, tool+"@"+version)
+}
+
 func normalizeFormulaName(name string) string {
 	// Remove version suffix (e.g., python@3.12 -> python)
 	re := regexp.MustCompile(`@[\d.]+$`)
@@ -245,76 +651,53 @@ func normalizeFormulaName(name string) string {
 	return normalized
 }
 
-func findCandidates(formulas []string, registry map[string]string, cfg *config.Config) []MigrationCandidate {
+func findCandidates(formulas []string, registry []RegistryEntry, cfg *config.Config) []MigrationCandidate {
 	var candidates []MigrationCandidate
+	index := indexRegistry(registry)
 
 	// Use known mappings from config, with fallback to default
 	knownMappings := cfg.Mise.KnownMappings
-	if knownMappings == nil || len(knownMappings) == 0 {
-		knownMappings = map[string]string{
-			"node":       "node",
-			"nodejs":     "node",
-			"python":     "python",
-			"python3":    "python",
-			"ruby":       "ruby",
-			"go":         "go",
-			"golang":     "go",
-			"rust":       "rust",
-			"rustup":     "rust",
-			"java":       "java",
-			"openjdk":    "java",
-			"deno":       "deno",
-			"bun":        "bun",
-			"terraform":  "terraform",
-			"kubectl":    "kubectl",
-			"helm":       "helm",
-			"awscli":     "awscli",
-			"yarn":       "yarn",
-			"pnpm":       "pnpm",
-			"gradle":     "gradle",
-			"maven":      "maven",
-			"kotlin":     "kotlin",
-			"scala":      "scala",
-			"elixir":     "elixir",
-			"erlang":     "erlang",
-			"lua":        "lua",
-			"luajit":     "luajit",
-			"perl":       "perl",
-			"php":        "php",
-			"zig":        "zig",
-			"nim":        "nim",
-			"crystal":    "crystal",
-			"julia":      "julia",
-			"r":          "r",
-			"dotnet":     "dotnet",
-			"flutter":    "flutter",
-			"dart":       "dart",
-		}
+	if len(knownMappings) == 0 {
+		knownMappings = config.DefaultConfig().Mise.KnownMappings
 	}
 
 	for _, formula := range formulas {
 		normalized := normalizeFormulaName(formula)
+		mapping, mapped := knownMappings[normalized]
+		tool := mapping.Tool
+		if tool == "" {
+			tool = normalized
+		}
 
-		// Check known mappings first
-		if miseName, ok := knownMappings[normalized]; ok {
-			if _, exists := registry[miseName]; exists {
-				candidates = append(candidates, MigrationCandidate{
-					BrewName:       formula,
-					NormalizedName: normalized,
-					MiseName:       miseName,
-				})
+		var c *MigrationCandidate
+		switch {
+		case mapped && isBackendSpec(tool):
+			// Backend-qualified specs are installed as is, without a registry entry
+			c = &MigrationCandidate{MiseName: tool, Backend: tool, Reason: MatchBackend}
+		case mapped:
+			// Check known mappings first
+			if m, exists := index[strings.ToLower(tool)]; exists {
+				c = &MigrationCandidate{MiseName: m.Entry.Short, Backend: m.Backend, Reason: MatchMapping}
+			}
+		}
+
+		// Check short names, aliases and backend repos in registry
+		if c == nil {
+			m, exists := index[normalized]
+			if !exists {
 				continue
 			}
+			c = &MigrationCandidate{MiseName: m.Entry.Short, Backend: m.Backend, Reason: m.Reason}
 		}
 
-		// Check direct match in registry
-		if miseName, exists := registry[normalized]; exists {
-			candidates = append(candidates, MigrationCandidate{
-				BrewName:       formula,
-				NormalizedName: normalized,
-				MiseName:       miseName,
-			})
+		c.BrewName = formula
+		c.NormalizedName = normalized
+		if mapped {
+			c.PinnedVersion = mapping.Version
+			c.NoUninstall = mapping.UninstallBrew != nil && !*mapping.UninstallBrew
+			c.SmokeTest = mapping.SmokeTest
 		}
+		candidates = append(candidates, *c)
 	}
 
 	return candidates
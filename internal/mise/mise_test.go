@@ -4,9 +4,19 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/BurntSushi/toml"
 	"github.com/yyYank/goodbye/internal/config"
 )
 
+// registryOf builds registry entries that only have short names
+func registryOf(names ...string) []RegistryEntry {
+	var registry []RegistryEntry
+	for _, name := range names {
+		registry = append(registry, RegistryEntry{Short: name})
+	}
+	return registry
+}
+
 func TestNormalizeFormulaName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -77,18 +87,7 @@ func TestNormalizeFormulaName(t *testing.T) {
 
 func TestFindCandidates(t *testing.T) {
 	// Mock registry with common tools
-	registry := map[string]string{
-		"node":      "node",
-		"python":    "python",
-		"go":        "go",
-		"ruby":      "ruby",
-		"rust":      "rust",
-		"java":      "java",
-		"deno":      "deno",
-		"bun":       "bun",
-		"terraform": "terraform",
-		"kubectl":   "kubectl",
-	}
+	registry := registryOf("node", "python", "go", "ruby", "rust", "java", "deno", "bun", "terraform", "kubectl")
 
 	tests := []struct {
 		name     string
@@ -109,73 +108,73 @@ func TestFindCandidates(t *testing.T) {
 			name:     "single matching formula",
 			formulas: []string{"node"},
 			expected: []MigrationCandidate{
-				{BrewName: "node", NormalizedName: "node", MiseName: "node"},
+				{BrewName: "node", NormalizedName: "node", MiseName: "node", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "multiple matching formulas",
 			formulas: []string{"node", "python", "go"},
 			expected: []MigrationCandidate{
-				{BrewName: "node", NormalizedName: "node", MiseName: "node"},
-				{BrewName: "python", NormalizedName: "python", MiseName: "python"},
-				{BrewName: "go", NormalizedName: "go", MiseName: "go"},
+				{BrewName: "node", NormalizedName: "node", MiseName: "node", Reason: MatchMapping},
+				{BrewName: "python", NormalizedName: "python", MiseName: "python", Reason: MatchMapping},
+				{BrewName: "go", NormalizedName: "go", MiseName: "go", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "formula with version suffix",
 			formulas: []string{"python@3.12"},
 			expected: []MigrationCandidate{
-				{BrewName: "python@3.12", NormalizedName: "python", MiseName: "python"},
+				{BrewName: "python@3.12", NormalizedName: "python", MiseName: "python", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "mixed matching and non-matching",
 			formulas: []string{"vim", "node", "neovim", "python", "tmux"},
 			expected: []MigrationCandidate{
-				{BrewName: "node", NormalizedName: "node", MiseName: "node"},
-				{BrewName: "python", NormalizedName: "python", MiseName: "python"},
+				{BrewName: "node", NormalizedName: "node", MiseName: "node", Reason: MatchMapping},
+				{BrewName: "python", NormalizedName: "python", MiseName: "python", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "known mapping - nodejs to node",
 			formulas: []string{"nodejs"},
 			expected: []MigrationCandidate{
-				{BrewName: "nodejs", NormalizedName: "nodejs", MiseName: "node"},
+				{BrewName: "nodejs", NormalizedName: "nodejs", MiseName: "node", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "known mapping - golang to go",
 			formulas: []string{"golang"},
 			expected: []MigrationCandidate{
-				{BrewName: "golang", NormalizedName: "golang", MiseName: "go"},
+				{BrewName: "golang", NormalizedName: "golang", MiseName: "go", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "known mapping - python3 to python",
 			formulas: []string{"python3"},
 			expected: []MigrationCandidate{
-				{BrewName: "python3", NormalizedName: "python3", MiseName: "python"},
+				{BrewName: "python3", NormalizedName: "python3", MiseName: "python", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "known mapping - rustup to rust",
 			formulas: []string{"rustup"},
 			expected: []MigrationCandidate{
-				{BrewName: "rustup", NormalizedName: "rustup", MiseName: "rust"},
+				{BrewName: "rustup", NormalizedName: "rustup", MiseName: "rust", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "known mapping - openjdk to java",
 			formulas: []string{"openjdk"},
 			expected: []MigrationCandidate{
-				{BrewName: "openjdk", NormalizedName: "openjdk", MiseName: "java"},
+				{BrewName: "openjdk", NormalizedName: "openjdk", MiseName: "java", Reason: MatchMapping},
 			},
 		},
 		{
 			name:     "direct registry match",
 			formulas: []string{"terraform"},
 			expected: []MigrationCandidate{
-				{BrewName: "terraform", NormalizedName: "terraform", MiseName: "terraform"},
+				{BrewName: "terraform", NormalizedName: "terraform", MiseName: "terraform", Reason: MatchMapping},
 			},
 		},
 	}
@@ -191,7 +190,7 @@ func TestFindCandidates(t *testing.T) {
 }
 
 func TestFindCandidatesWithEmptyRegistry(t *testing.T) {
-	registry := map[string]string{}
+	registry := []RegistryEntry{}
 	formulas := []string{"node", "python", "go"}
 
 	result := findCandidates(formulas, registry, config.DefaultConfig())
@@ -263,21 +262,7 @@ func TestRegistryEntryStruct(t *testing.T) {
 
 func TestKnownMappings(t *testing.T) {
 	// Test that known mappings cover common tools
-	registry := map[string]string{
-		"node":      "node",
-		"python":    "python",
-		"go":        "go",
-		"ruby":      "ruby",
-		"rust":      "rust",
-		"java":      "java",
-		"deno":      "deno",
-		"bun":       "bun",
-		"terraform": "terraform",
-		"kubectl":   "kubectl",
-		"helm":      "helm",
-		"yarn":      "yarn",
-		"pnpm":      "pnpm",
-	}
+	registry := registryOf("node", "python", "go", "ruby", "rust", "java", "deno", "bun", "terraform", "kubectl", "helm", "yarn", "pnpm")
 
 	// These should all map correctly
 	knownMappingTests := []struct {
@@ -358,3 +343,324 @@ func TestNormalizeFormulaNameEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestBackendRepoName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"aqua:BurntSushi/ripgrep", "ripgrep"},
+		{"ubi:sharkdp/fd[exe=fd]", "fd"},
+		{"core:node", "node"},
+		{"cargo:eza", "eza"},
+		{"npm:@biomejs/biome", "biome"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if result := backendRepoName(tt.input); result != tt.expected {
+			t.Errorf("backendRepoName(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFindCandidatesWithRegistryEntries(t *testing.T) {
+	registry := []RegistryEntry{
+		{Short: "node", Backends: []string{"core:node"}, Aliases: []string{"nodejs"}},
+		{Short: "ripgrep", Full: "aqua:BurntSushi/ripgrep ubi:BurntSushi/ripgrep[exe=rg]", Aliases: []string{"rg"}},
+		{Short: "fd", Backends: []string{"aqua:sharkdp/fd"}},
+		{Short: "github-cli", Backends: []string{"aqua:cli/cli"}, Aliases: []string{"gh"}},
+		{Short: "cli", Backends: []string{"asdf:someone/cli"}},
+	}
+	cfg := config.DefaultConfig()
+	cfg.Mise.KnownMappings = map[string]config.MiseMapping{"nodejs": {Tool: "node"}}
+
+	tests := []struct {
+		name     string
+		formulas []string
+		expected []MigrationCandidate
+	}{
+		{
+			name:     "known mapping",
+			formulas: []string{"nodejs"},
+			expected: []MigrationCandidate{
+				{BrewName: "nodejs", NormalizedName: "nodejs", MiseName: "node", Backend: "core:node", Reason: MatchMapping},
+			},
+		},
+		{
+			name:     "short name uses preferred backend",
+			formulas: []string{"ripgrep"},
+			expected: []MigrationCandidate{
+				{BrewName: "ripgrep", NormalizedName: "ripgrep", MiseName: "ripgrep", Backend: "aqua:BurntSushi/ripgrep", Reason: MatchShort},
+			},
+		},
+		{
+			name:     "alias",
+			formulas: []string{"gh"},
+			expected: []MigrationCandidate{
+				{BrewName: "gh", NormalizedName: "gh", MiseName: "github-cli", Backend: "aqua:cli/cli", Reason: MatchAlias},
+			},
+		},
+		{
+			name:     "short name wins over backend repo",
+			formulas: []string{"cli"},
+			expected: []MigrationCandidate{
+				{BrewName: "cli", NormalizedName: "cli", MiseName: "cli", Backend: "asdf:someone/cli", Reason: MatchShort},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := findCandidates(tt.formulas, registry, cfg)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("findCandidates() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindCandidatesMatchesBackendRepo(t *testing.T) {
+	registry := []RegistryEntry{
+		{Short: "bat-extras", Backends: []string{"aqua:eth-p/bat-extras"}},
+		{Short: "rg", Backends: []string{"aqua:BurntSushi/ripgrep"}},
+	}
+	cfg := config.DefaultConfig()
+	cfg.Mise.KnownMappings = map[string]config.MiseMapping{"unused": {Tool: "unused"}}
+
+	result := findCandidates([]string{"ripgrep"}, registry, cfg)
+	expected := []MigrationCandidate{
+		{BrewName: "ripgrep", NormalizedName: "ripgrep", MiseName: "rg", Backend: "aqua:BurntSushi/ripgrep", Reason: MatchRepo},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("findCandidates() = %+v, want %+v", result, expected)
+	}
+}
+
+func TestFindCandidatesWithMappingOptions(t *testing.T) {
+	registry := registryOf("node")
+	noUninstall := false
+	cfg := config.DefaultConfig()
+	cfg.Mise.KnownMappings = map[string]config.MiseMapping{
+		"gh":       {Tool: "aqua:cli/cli"},
+		"prettier": {Tool: "npm:prettier", Version: "3", SmokeTest: "prettier --version"},
+		"node":     {Tool: "node", Version: "20", UninstallBrew: &noUninstall},
+	}
+
+	result := findCandidates([]string{"gh", "prettier", "node", "jq"}, registry, cfg)
+	expected := []MigrationCandidate{
+		{BrewName: "gh", NormalizedName: "gh", MiseName: "aqua:cli/cli", Backend: "aqua:cli/cli", Reason: MatchBackend},
+		{BrewName: "prettier", NormalizedName: "prettier", MiseName: "npm:prettier", Backend: "npm:prettier", Reason: MatchBackend, PinnedVersion: "3", SmokeTest: "prettier --version"},
+		{BrewName: "node", NormalizedName: "node", MiseName: "node", Reason: MatchMapping, PinnedVersion: "20", NoUninstall: true},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("findCandidates() = %+v, want %+v", result, expected)
+	}
+}
+
+func TestMappingOptions(t *testing.T) {
+	tests := []struct {
+		candidate MigrationCandidate
+		expected  string
+	}{
+		{MigrationCandidate{}, "-"},
+		{MigrationCandidate{PinnedVersion: "20"}, "version=20"},
+		{MigrationCandidate{PinnedVersion: "3", NoUninstall: true, SmokeTest: "prettier --version"}, "version=3, uninstall_brew=false, smoke_test=prettier --version"},
+	}
+
+	for _, tt := range tests {
+		if result := mappingOptions(tt.candidate); result != tt.expected {
+			t.Errorf("mappingOptions(%+v) = %q, want %q", tt.candidate, result, tt.expected)
+		}
+	}
+}
+
+func TestDecodeKnownMappings(t *testing.T) {
+	data := `
+[mise.known_mappings]
+nodejs = "node"
+gh = "aqua:cli/cli"
+"python@3.12" = { tool = "python", version = "3.12", uninstall_brew = false, smoke_test = "python3 --version" }
+`
+	var cfg config.Config
+	if _, err := toml.Decode(data, &cfg); err != nil {
+		t.Fatalf("toml.Decode() error = %v", err)
+	}
+
+	if got := cfg.Mise.KnownMappings["nodejs"]; got.Tool != "node" || got.UninstallBrew != nil {
+		t.Errorf("nodejs mapping = %+v", got)
+	}
+	if got := cfg.Mise.KnownMappings["gh"]; got.Tool != "aqua:cli/cli" {
+		t.Errorf("gh mapping = %+v", got)
+	}
+	got := cfg.Mise.KnownMappings["python@3.12"]
+	if got.Tool != "python" || got.Version != "3.12" || got.SmokeTest != "python3 --version" || got.UninstallBrew == nil || *got.UninstallBrew {
+		t.Errorf("python@3.12 mapping = %+v", got)
+	}
+}
+
+func TestGetMiseRegistry(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Mise.Commands.RegistryJSONCmd = `echo '[{"short":"ripgrep","full":"aqua:BurntSushi/ripgrep","aliases":["rg"]}]'`
+
+		registry, err := getMiseRegistryJSON(cfg)
+		if err != nil {
+			t.Fatalf("getMiseRegistryJSON() error = %v", err)
+		}
+		expected := []RegistryEntry{{Short: "ripgrep", Full: "aqua:BurntSushi/ripgrep", Aliases: []string{"rg"}}}
+		if !reflect.DeepEqual(registry, expected) {
+			t.Errorf("getMiseRegistryJSON() = %+v, want %+v", registry, expected)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.Mise.Commands.RegistryCmd = `printf 'node  core:node\nripgrep  aqua:BurntSushi/ripgrep ubi:BurntSushi/ripgrep\n'`
+
+		registry, err := getMiseRegistry(cfg)
+		if err != nil {
+			t.Fatalf("getMiseRegistry() error = %v", err)
+		}
+		expected := []RegistryEntry{
+			{Short: "node", Full: "core:node"},
+			{Short: "ripgrep", Full: "aqua:BurntSushi/ripgrep ubi:BurntSushi/ripgrep"},
+		}
+		if !reflect.DeepEqual(registry, expected) {
+			t.Errorf("getMiseRegistry() = %+v, want %+v", registry, expected)
+		}
+	})
+}
+
+func TestResolveVersion(t *testing.T) {
+	cfg := config.DefaultConfig()
+	// "brew list --versions" output for every formula
+	cfg.Mise.Commands.BrewVersionsCmd = `echo %s 22.1.0 22.3.0_1`
+
+	tests := []struct {
+		name     string
+		formula  string
+		strategy string
+		expected string
+	}{
+		{"latest", "python@3.11", VersionLatest, "latest"},
+		{"keep-major with versioned formula", "python@3.11", VersionKeepMajor, "3.11"},
+		{"keep-major with single-number suffix", "node@18", VersionKeepMajor, "18"},
+		{"keep-major from keg", "node", VersionKeepMajor, "22"},
+		{"keep-exact from keg", "node", VersionKeepExact, "22.3.0"},
+		{"keep-exact ignores suffix", "python@3.11", VersionKeepExact, "22.3.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolveVersion(cfg, tt.formula, tt.strategy)
+			if err != nil {
+				t.Fatalf("resolveVersion() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("resolveVersion(%q, %q) = %q, want %q", tt.formula, tt.strategy, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveVersionWithoutKeg(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewVersionsCmd = "true %s"
+
+	if _, err := resolveVersion(cfg, "node", VersionKeepExact); err == nil {
+		t.Error("resolveVersion() should fail when no keg is installed")
+	}
+	if result, err := resolveVersion(cfg, "node@18", VersionKeepMajor); err != nil || result != "18" {
+		t.Errorf("resolveVersion() = %q, %v, want 18 without a keg lookup", result, err)
+	}
+}
+
+func TestBrewKegVersionKegsOutOfOrder(t *testing.T) {
+	cfg := config.DefaultConfig()
+	// brew lists kegs in directory order, so the newest one may come first
+	cfg.Mise.Commands.BrewVersionsCmd = "echo %s 22.10.0 22.9.0_1"
+
+	if result, err := brewKegVersion(cfg, "node"); err != nil || result != "22.10.0" {
+		t.Errorf("brewKegVersion() = %q, %v, want 22.10.0", result, err)
+	}
+	if result, err := resolveVersion(cfg, "node", VersionKeepExact); err != nil || result != "22.10.0" {
+		t.Errorf("resolveVersion(keep-exact) = %q, %v, want 22.10.0", result, err)
+	}
+}
+
+func TestVersionStrategy(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.VersionStrategy = VersionKeepExact
+
+	if result, _ := versionStrategy(cfg, MigrateOptions{}); result != VersionKeepExact {
+		t.Errorf("versionStrategy() = %q, want config value %q", result, VersionKeepExact)
+	}
+	if result, _ := versionStrategy(cfg, MigrateOptions{VersionStrategy: VersionLatest}); result != VersionLatest {
+		t.Errorf("versionStrategy() = %q, want option value %q", result, VersionLatest)
+	}
+	if _, err := versionStrategy(cfg, MigrateOptions{VersionStrategy: "newest"}); err == nil {
+		t.Error("versionStrategy() should reject unknown strategies")
+	}
+}
+
+func TestToolCommand(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"mise install %s", "mise install python@3.11"},
+		{"mise install %s@latest", "mise install python@3.11"},
+		{"mise use -g %s", "mise use -g python@3.11"},
+	}
+
+	for _, tt := range tests {
+		if result := toolCommand(tt.template, "python", "3.11"); result != tt.expected {
+			t.Errorf("toolCommand(%q) = %q, want %q", tt.template, result, tt.expected)
+		}
+	}
+}
+
+func TestBrewDependents(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.BrewUsesCmd = `sh -c 'test "$0" = python@3.12 && printf "awscli\nglib\n"; true' %s`
+
+	dependents, err := brewDependents(cfg, "python@3.12")
+	if err != nil {
+		t.Fatalf("brewDependents() error = %v", err)
+	}
+	if !reflect.DeepEqual(dependents, []string{"awscli", "glib"}) {
+		t.Errorf("brewDependents() = %v, want [awscli glib]", dependents)
+	}
+
+	dependents, err = brewDependents(cfg, "node")
+	if err != nil || len(dependents) != 0 {
+		t.Errorf("brewDependents(node) = %v, %v, want none", dependents, err)
+	}
+
+	cfg.Mise.Commands.BrewUsesCmd = "false %s"
+	if _, err := brewDependents(cfg, "node"); err == nil {
+		t.Error("brewDependents() should return error when brew uses fails")
+	}
+}
+
+func TestDependentsSummary(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate MigrationCandidate
+		expected  string
+	}{
+		{"no dependents", MigrationCandidate{}, "-"},
+		{"dependents", MigrationCandidate{Dependents: []string{"awscli", "glib"}, KeepBrew: true}, "required by awscli, glib"},
+		{"many dependents", MigrationCandidate{Dependents: []string{"a", "b", "c", "d", "e"}, KeepBrew: true}, "required by a, b, c and 2 more"},
+		{"check failed", MigrationCandidate{KeepBrew: true}, "dependents unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := dependentsSummary(tt.candidate); result != tt.expected {
+				t.Errorf("dependentsSummary() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
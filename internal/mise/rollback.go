@@ -0,0 +1,206 @@
+package mise
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// RollbackOptions represents options for the brew --mise --rollback command
+type RollbackOptions struct {
+	DryRun      bool
+	Verbose     bool
+	Only        []string // roll back only these tools (brew, normalized or mise names)
+	JournalPath string   // "" for ~/.local/state/goodbye/mise-journal.json
+}
+
+// Rollback undoes recorded brew -> mise migrations: the brew formula is
+// reinstalled, the tool is removed from the global mise config and
+// uninstalled, and the journal entry is marked as reverted
+func Rollback(cfg *config.Config, opts RollbackOptions) error {
+	journal, err := loadJournal(opts.JournalPath)
+	if err != nil {
+		return fmt.Errorf("failed to load migration journal: %w", err)
+	}
+
+	entries := journal.revertible(opts.Only)
+	if len(entries) == 0 {
+		fmt.Println("No migrations to roll back.")
+		return nil
+	}
+
+	fmt.Printf("Found %d migrations to roll back:\n", len(entries))
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-25s %-15s %-10s %-12s %s\n", "BREW", "MISE", "VERSION", "BREW KEG", "MIGRATED AT")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, e := range entries {
+		fmt.Printf("%-25s %-15s %-10s %-12s %s\n", e.BrewName, e.MiseName, e.Version, e.BrewVersion, e.MigratedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Println(strings.Repeat("-", 80))
+
+	cmds := commandsFor(cfg)
+
+	if opts.DryRun {
+		fmt.Println("\n[dry-run] Would perform the following actions:")
+		for _, e := range entries {
+			formula, err := rollbackFormula(cfg, e)
+			if err != nil {
+				fmt.Printf("  %s: %v\n\n", e.BrewName, err)
+				continue
+			}
+			for i, step := range rollbackSteps(e, formula, cmds) {
+				fmt.Printf("  %d. %s\n", i+1, step)
+			}
+			fmt.Println()
+		}
+		fmt.Println("\nTo apply these changes, run with --apply")
+		return nil
+	}
+
+	fmt.Print("\nDo you want to roll back these migrations? [y/N]: ")
+	var response string
+	fmt.Scanln(&response)
+	if strings.ToLower(response) != "y" {
+		fmt.Println("Rollback cancelled.")
+		return nil
+	}
+
+	var reverted, failed []*JournalEntry
+	for _, e := range entries {
+		fmt.Printf("\nRolling back %s -> %s@%s\n", e.BrewName, e.MiseName, e.Version)
+		if err := rollbackEntry(cfg, journal, e, cmds, opts.Verbose); err != nil {
+			fmt.Printf("  %v\n", err)
+			failed = append(failed, e)
+			continue
+		}
+		fmt.Printf("  Rolled back %s\n", e.BrewName)
+		reverted = append(reverted, e)
+	}
+
+	// Summary
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("Rollback Summary")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("Reverted: %d\n", len(reverted))
+	for _, e := range reverted {
+		fmt.Printf("  - %s@%s -> %s\n", e.MiseName, e.Version, e.BrewName)
+	}
+	if len(failed) > 0 {
+		fmt.Printf("Failed: %d\n", len(failed))
+		for _, e := range failed {
+			fmt.Printf("  - %s\n", e.BrewName)
+		}
+	}
+
+	return nil
+}
+
+// rollbackSteps returns the commands that roll back an entry. The brew
+// formula is only reinstalled if the migration removed it.
+func rollbackSteps(e *JournalEntry, formula string, cmds migrationCommands) []string {
+	var steps []string
+	if e.Status != JournalRetained {
+		steps = append(steps, fmt.Sprintf(cmds.BrewInstall, formula))
+	}
+	return append(steps,
+		toolCommand(cmds.UnuseGlobal, e.MiseName, e.Version),
+		toolCommand(cmds.Uninstall, e.MiseName, e.Version),
+	)
+}
+
+// rollbackEntry runs the rollback steps of an entry. The brew formula is
+// reinstalled first so the tool stays available if removing it from mise
+// fails. An entry whose recorded version brew can no longer install is
+// left as it is and reported as failed.
+func rollbackEntry(cfg *config.Config, journal *Journal, e *JournalEntry, cmds migrationCommands, verbose bool) error {
+	formula, err := rollbackFormula(cfg, e)
+	if err != nil {
+		return err
+	}
+	if formula != e.BrewName {
+		fmt.Printf("  %s no longer provides %s; installing %s\n", e.BrewName, e.BrewVersion, formula)
+	}
+
+	for _, step := range rollbackSteps(e, formula, cmds) {
+		fmt.Printf("  Running %s...\n", step)
+		if err := journal.run(e, step, verbose); err != nil {
+			return fmt.Errorf("failed to run %s: %w", step, err)
+		}
+	}
+
+	journal.setStatus(e, JournalReverted)
+	return nil
+}
+
+// rollbackFormula returns the formula that reinstalls the recorded keg
+// version's series, most specific first: the formula itself while its
+// stable version is in the series, otherwise a versioned formula (node@20,
+// python@3.12). Versioned formulas and entries without a recorded version
+// are installed as they are.
+func rollbackFormula(cfg *config.Config, e *JournalEntry) (string, error) {
+	if e.Status == JournalRetained || e.BrewVersion == "" || strings.Contains(e.BrewName, "@") {
+		return e.BrewName, nil
+	}
+
+	stable, err := brewStableVersion(cfg, e.BrewName)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", e.BrewName, err)
+	}
+
+	series := versionSeries(e.BrewVersion)
+	var tried []string
+	for _, s := range series {
+		if stable == s || strings.HasPrefix(stable, s+".") {
+			return e.BrewName, nil
+		}
+		formula := e.BrewName + "@" + s
+		if _, err := brewStableVersion(cfg, formula); err == nil {
+			return formula, nil
+		}
+		tried = append(tried, formula)
+	}
+	return "", fmt.Errorf("cannot restore %s %s: brew now provides %s and there is no %s formula",
+		e.BrewName, e.BrewVersion, stable, strings.Join(tried, " or "))
+}
+
+// versionSeries returns the versioned formula suffixes for a version,
+// most specific first (e.g., 3.12.4 -> 3.12, 3)
+func versionSeries(version string) []string {
+	parts := strings.Split(version, ".")
+	if len(parts) >= 2 {
+		return []string{parts[0] + "." + parts[1], parts[0]}
+	}
+	return []string{parts[0]}
+}
+
+// brewStableVersion returns the stable version brew provides for a formula
+func brewStableVersion(cfg *config.Config, formula string) (string, error) {
+	cmdTemplate := cfg.Mise.Commands.BrewInfoCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "brew info --json=v2 %s"
+	}
+
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(cmdTemplate, formula))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	var info struct {
+		Formulae []struct {
+			Versions struct {
+				Stable string `json:"stable"`
+			} `json:"versions"`
+		} `json:"formulae"`
+	}
+	if err := json.Unmarshal(output, &info); err != nil {
+		return "", err
+	}
+	if len(info.Formulae) == 0 || info.Formulae[0].Versions.Stable == "" {
+		return "", fmt.Errorf("no formula named %s", formula)
+	}
+	return info.Formulae[0].Versions.Stable, nil
+}
@@ -0,0 +1,111 @@
+package mise
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Answers to the per-candidate prompt of --interactive
+const (
+	answerYes         = "yes"
+	answerNo          = "no"
+	answerSkipForever = "skip-forever"
+)
+
+// matchesName reports whether a candidate is referred to by name, either by
+// its brew name, its normalized name or its mise name
+func (c MigrationCandidate) matchesName(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return name != "" && (name == strings.ToLower(c.BrewName) || name == c.NormalizedName || name == strings.ToLower(c.MiseName))
+}
+
+func matchesAny(c MigrationCandidate, names []string) bool {
+	for _, name := range names {
+		if c.matchesName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCandidates keeps the candidates named in only (all of them if only
+// is empty) and drops the ones named in exclude. It also returns the names
+// in only that matched no candidate.
+func filterCandidates(candidates []MigrationCandidate, only, exclude []string) ([]MigrationCandidate, []string) {
+	var filtered []MigrationCandidate
+	for _, c := range candidates {
+		if len(only) > 0 && !matchesAny(c, only) {
+			continue
+		}
+		if matchesAny(c, exclude) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	var unknown []string
+	for _, name := range only {
+		found := false
+		for _, c := range candidates {
+			if c.matchesName(name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, name)
+		}
+	}
+	return filtered, unknown
+}
+
+// promptCandidates asks for each candidate whether to migrate it.
+// It returns the candidates to migrate and the ones to skip forever.
+func promptCandidates(candidates []MigrationCandidate, in io.Reader, w io.Writer) (selected, skipForever []MigrationCandidate) {
+	reader := bufio.NewReader(in)
+	for _, c := range candidates {
+		fmt.Fprintf(w, "Migrate %s -> %s@%s? [y/N/s(kip forever)]: ", c.BrewName, c.MiseName, c.Version)
+		response, _ := reader.ReadString('\n')
+
+		switch parseAnswer(response) {
+		case answerYes:
+			selected = append(selected, c)
+		case answerSkipForever:
+			fmt.Fprintf(w, "  %s will not be suggested again.\n", c.BrewName)
+			skipForever = append(skipForever, c)
+		default:
+			fmt.Fprintln(w, "  Skipped.")
+		}
+	}
+	return selected, skipForever
+}
+
+func parseAnswer(response string) string {
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "y", "yes":
+		return answerYes
+	case "s", "skip", "skip-forever":
+		return answerSkipForever
+	}
+	return answerNo
+}
+
+// addExcludes appends the brew names of candidates to an exclude list,
+// skipping names that are already listed
+func addExcludes(exclude []string, candidates []MigrationCandidate) []string {
+	for _, c := range candidates {
+		listed := false
+		for _, name := range exclude {
+			if name == c.BrewName {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			exclude = append(exclude, c.BrewName)
+		}
+	}
+	return exclude
+}
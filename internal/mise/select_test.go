@@ -0,0 +1,81 @@
+package mise
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func brewNames(candidates []MigrationCandidate) []string {
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.BrewName)
+	}
+	return names
+}
+
+func TestFilterCandidates(t *testing.T) {
+	candidates := []MigrationCandidate{
+		{BrewName: "python@3.12", NormalizedName: "python", MiseName: "python"},
+		{BrewName: "node", NormalizedName: "node", MiseName: "node"},
+		{BrewName: "gh", NormalizedName: "gh", MiseName: "github-cli"},
+	}
+
+	tests := []struct {
+		name            string
+		only            []string
+		exclude         []string
+		expected        []string
+		expectedUnknown []string
+	}{
+		{"no filters", nil, nil, []string{"python@3.12", "node", "gh"}, nil},
+		{"only by normalized name", []string{"python"}, nil, []string{"python@3.12"}, nil},
+		{"only by mise name", []string{"github-cli", "node"}, nil, []string{"node", "gh"}, nil},
+		{"exclude by brew name", nil, []string{"python@3.12"}, []string{"node", "gh"}, nil},
+		{"exclude wins over only", []string{"node", "gh"}, []string{"GH"}, []string{"node"}, nil},
+		{"unknown only name", []string{"node", "ruby"}, nil, []string{"node"}, []string{"ruby"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, unknown := filterCandidates(candidates, tt.only, tt.exclude)
+			if names := brewNames(result); !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("filterCandidates() = %v, want %v", names, tt.expected)
+			}
+			if !reflect.DeepEqual(unknown, tt.expectedUnknown) {
+				t.Errorf("filterCandidates() unknown = %v, want %v", unknown, tt.expectedUnknown)
+			}
+		})
+	}
+}
+
+func TestPromptCandidates(t *testing.T) {
+	candidates := []MigrationCandidate{
+		{BrewName: "python@3.12", MiseName: "python", Version: "3.12"},
+		{BrewName: "node", MiseName: "node", Version: "22"},
+		{BrewName: "go", MiseName: "go", Version: "1"},
+		{BrewName: "ruby", MiseName: "ruby", Version: "3"},
+	}
+
+	// The last candidate gets no answer (EOF) and is skipped
+	in := strings.NewReader("y\nskip\nn\n")
+	selected, skipForever := promptCandidates(candidates, in, io.Discard)
+
+	if names := brewNames(selected); !reflect.DeepEqual(names, []string{"python@3.12"}) {
+		t.Errorf("selected = %v, want [python@3.12]", names)
+	}
+	if names := brewNames(skipForever); !reflect.DeepEqual(names, []string{"node"}) {
+		t.Errorf("skipForever = %v, want [node]", names)
+	}
+}
+
+func TestAddExcludes(t *testing.T) {
+	exclude := []string{"node"}
+	candidates := []MigrationCandidate{{BrewName: "node"}, {BrewName: "python@3.12"}}
+
+	result := addExcludes(exclude, candidates)
+	if !reflect.DeepEqual(result, []string{"node", "python@3.12"}) {
+		t.Errorf("addExcludes() = %v, want [node python@3.12]", result)
+	}
+}
@@ -0,0 +1,122 @@
+package mise
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/brew"
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// smokeTestFor returns the smoke test configured for a candidate: the
+// command from its known mapping, or the one in [mise.smoke_tests] looked
+// up by its mise name and then by its normalized brew name
+func smokeTestFor(cfg *config.Config, c MigrationCandidate) (config.SmokeTest, bool) {
+	if c.SmokeTest != "" {
+		return config.SmokeTest{Command: c.SmokeTest}, true
+	}
+	for _, name := range []string{c.MiseName, c.NormalizedName} {
+		if test, ok := cfg.Mise.SmokeTests[name]; ok && test.Command != "" {
+			return test, true
+		}
+	}
+	return config.SmokeTest{}, false
+}
+
+// runSmokeTest checks that mise provides the binary of a smoke test for
+// tool (tool@version), then runs the command through mise exec and matches
+// its output against the expectation. mise exec puts the version just
+// installed first on PATH, which goodbye's own PATH does not do when mise
+// is activated without shims.
+func runSmokeTest(cfg *config.Config, test config.SmokeTest, tool string) error {
+	binary := smokeBinary(test)
+	if binary == "" {
+		return fmt.Errorf("empty smoke test command")
+	}
+	if _, err := checkBinaryPath(cfg, binary, tool); err != nil {
+		return err
+	}
+
+	cmdTemplate := cfg.Mise.Commands.ExecCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "mise exec %s -- sh -c %s"
+	}
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(cmdTemplate, tool, shellQuote(test.Command)))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", test.Command, err)
+	}
+	if test.Expect == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile("(?m)" + test.Expect)
+	if err != nil {
+		return fmt.Errorf("invalid expected output %q: %w", test.Expect, err)
+	}
+	if !re.MatchString(strings.TrimSpace(string(output))) {
+		return fmt.Errorf("%s printed %q, expected to match %q", test.Command, strings.TrimSpace(string(output)), test.Expect)
+	}
+	return nil
+}
+
+// checkBinaryPath resolves a binary with mise which for tool (tool@version)
+// and verifies that it is a mise install or shim (not, e.g., the system
+// copy mise falls back to)
+func checkBinaryPath(cfg *config.Config, binary, tool string) (string, error) {
+	cmdTemplate := cfg.Mise.Commands.WhichCmd
+	if cmdTemplate == "" {
+		cmdTemplate = "mise which --tool %s %s"
+	}
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(cmdTemplate, tool, shellQuote(binary)))
+	output, err := cmd.Output()
+	path := strings.TrimSpace(string(output))
+	if err != nil || path == "" {
+		return "", fmt.Errorf("%s is not provided by %s", binary, tool)
+	}
+	if !isMisePath(path) {
+		return path, fmt.Errorf("%s resolves to %s, which is not a mise install or shim", binary, path)
+	}
+	return path, nil
+}
+
+// checkShadowed looks a binary up on goodbye's own PATH, as a shell without
+// mise exec would, and reports a Homebrew copy that shadows the mise
+// install. A binary that is not on this PATH (mise activate without shims
+// adds it only in the shell) is not an error.
+func checkShadowed(binary string) error {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil
+	}
+	if brew.IsHomebrewPath(path) {
+		return fmt.Errorf("%s still resolves to the Homebrew copy %s (check that mise comes first on PATH)", binary, path)
+	}
+	return nil
+}
+
+// smokeBinary returns the binary a smoke test runs
+func smokeBinary(test config.SmokeTest) string {
+	fields := strings.Fields(test.Command)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// shellQuote quotes s as a single sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isMisePath reports whether a path is inside a mise install or shim directory
+func isMisePath(path string) bool {
+	if dataDir := os.Getenv("MISE_DATA_DIR"); dataDir != "" && strings.HasPrefix(path, dataDir+string(filepath.Separator)) {
+		return true
+	}
+	return strings.Contains(path, "/mise/installs/") || strings.Contains(path, "/mise/shims/")
+}
@@ -0,0 +1,177 @@
+package mise
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// writeBinary creates an executable script at dir/name that prints output
+func writeBinary(t *testing.T, dir, name, output string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	script := "#!/bin/sh\necho '" + output + "'\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+// fakeMiseWhich returns a mise which command that resolves binaries in dir
+// and fails for the ones that do not exist there
+func fakeMiseWhich(dir string) string {
+	return `sh -c 'test -x "` + dir + `/$2" && echo "` + dir + `/$2"' _ %s %s`
+}
+
+func TestCheckBinaryPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	installs := filepath.Join(tmpDir, "mise", "installs", "tool", "1", "bin")
+	brewBin := filepath.Join(tmpDir, "brew", "bin")
+	otherBin := filepath.Join(tmpDir, "other", "bin")
+	writeBinary(t, installs, "tool-mise", "1")
+	writeBinary(t, brewBin, "tool-brew", "1")
+	writeBinary(t, otherBin, "tool-other", "1")
+
+	t.Setenv("HOMEBREW_PREFIX", filepath.Join(tmpDir, "brew"))
+	t.Setenv("MISE_DATA_DIR", "")
+
+	tests := []struct {
+		binary  string
+		which   string
+		wantErr string
+	}{
+		{"tool-mise", fakeMiseWhich(installs), ""},
+		{"tool-brew", fakeMiseWhich(brewBin), "not a mise install or shim"},
+		{"tool-other", fakeMiseWhich(otherBin), "not a mise install or shim"},
+		{"tool-missing", fakeMiseWhich(installs), "not provided by tool@1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.binary, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Mise.Commands.WhichCmd = tt.which
+
+			_, err := checkBinaryPath(cfg, tt.binary, "tool@1")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkBinaryPath() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkBinaryPath() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunSmokeTest(t *testing.T) {
+	installs := filepath.Join(t.TempDir(), "mise", "installs", "python", "3.12", "bin")
+	writeBinary(t, installs, "fakepython", "1")
+	t.Setenv("MISE_DATA_DIR", "")
+
+	// fakepython is only on the PATH of mise exec, like a version installed
+	// with mise activate and no shims
+	cfg := config.DefaultConfig()
+	cfg.Mise.Commands.WhichCmd = fakeMiseWhich(installs)
+	cfg.Mise.Commands.ExecCmd = `sh -c 'test "$1" = python@3.12 && PATH="` + installs + `:$PATH" sh -c "$2"' _ %s %s`
+
+	tests := []struct {
+		name    string
+		test    config.SmokeTest
+		tool    string
+		wantErr bool
+	}{
+		{"matching output", config.SmokeTest{Command: "fakepython -c 'print(1)'", Expect: `^1$`}, "python@3.12", false},
+		{"exit status only", config.SmokeTest{Command: "fakepython"}, "python@3.12", false},
+		{"unexpected output", config.SmokeTest{Command: "fakepython", Expect: `^2$`}, "python@3.12", true},
+		{"failing command", config.SmokeTest{Command: "fakepython && false"}, "python@3.12", true},
+		{"binary not from mise", config.SmokeTest{Command: "sh -c true"}, "python@3.12", true},
+		{"other version", config.SmokeTest{Command: "fakepython"}, "python@3.11", true},
+		{"invalid regex", config.SmokeTest{Command: "fakepython", Expect: `(`}, "python@3.12", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runSmokeTest(cfg, tt.test, tt.tool)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runSmokeTest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckShadowed(t *testing.T) {
+	tmpDir := t.TempDir()
+	installs := filepath.Join(tmpDir, "mise", "installs", "tool", "1", "bin")
+	brewBin := filepath.Join(tmpDir, "brew", "bin")
+	writeBinary(t, installs, "tool-mise", "1")
+	writeBinary(t, brewBin, "tool-brew", "1")
+	writeBinary(t, brewBin, "tool-mise", "1")
+
+	t.Setenv("HOMEBREW_PREFIX", filepath.Join(tmpDir, "brew"))
+
+	tests := []struct {
+		name    string
+		path    []string
+		binary  string
+		wantErr bool
+	}{
+		{"mise first on PATH", []string{installs, brewBin}, "tool-mise", false},
+		{"brew copy first on PATH", []string{brewBin, installs}, "tool-mise", true},
+		{"only the brew copy", []string{installs, brewBin}, "tool-brew", true},
+		{"not on PATH (mise activate without shims)", []string{brewBin}, "tool-missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PATH", strings.Join(tt.path, string(os.PathListSeparator)))
+			err := checkShadowed(tt.binary)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkShadowed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSmokeTestFor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mise.SmokeTests["nodejs"] = config.SmokeTest{Command: "node --version"}
+
+	if test, ok := smokeTestFor(cfg, MigrationCandidate{MiseName: "python", NormalizedName: "python"}); !ok || !strings.HasPrefix(test.Command, "python3") {
+		t.Errorf("smokeTestFor(python) = %+v, %v, want built-in default", test, ok)
+	}
+	if test, ok := smokeTestFor(cfg, MigrationCandidate{MiseName: "node18", NormalizedName: "nodejs"}); !ok || test.Command != "node --version" {
+		t.Errorf("smokeTestFor(nodejs) = %+v, %v, want lookup by normalized name", test, ok)
+	}
+	if test, ok := smokeTestFor(cfg, MigrationCandidate{MiseName: "python", NormalizedName: "python", SmokeTest: "python3 --version"}); !ok || test.Command != "python3 --version" {
+		t.Errorf("smokeTestFor(python) = %+v, %v, want the known mapping command", test, ok)
+	}
+	if _, ok := smokeTestFor(cfg, MigrationCandidate{MiseName: "terraform", NormalizedName: "terraform"}); ok {
+		t.Error("smokeTestFor(terraform) should have no smoke test")
+	}
+}
+
+func TestDecodeSmokeTests(t *testing.T) {
+	data := `
+[mise.smoke_tests]
+python = "python3 -c 'import ssl; print(1)'"
+node = { command = "node --version", expect = "^v20" }
+`
+	var cfg config.Config
+	if _, err := toml.Decode(data, &cfg); err != nil {
+		t.Fatalf("toml.Decode() error = %v", err)
+	}
+
+	if got := cfg.Mise.SmokeTests["python"]; got.Command != "python3 -c 'import ssl; print(1)'" || got.Expect != "" {
+		t.Errorf("python smoke test = %+v", got)
+	}
+	if got := cfg.Mise.SmokeTests["node"]; got.Command != "node --version" || got.Expect != "^v20" {
+		t.Errorf("node smoke test = %+v", got)
+	}
+}
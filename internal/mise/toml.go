@@ -0,0 +1,312 @@
+package mise
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigFile represents a mise configuration file (.mise.toml). Tools keep
+// the order of the file; the other sections are kept as decoded so that
+// writing the file back does not lose them.
+type ConfigFile struct {
+	Tools    []Tool
+	Env      map[string]interface{}
+	Tasks    map[string]interface{}
+	Settings map[string]interface{}
+	Plugins  map[string]interface{}
+	Other    map[string]interface{} // other top-level keys and tables (e.g., min_version, [alias], [hooks])
+}
+
+// Tool represents a tool in [tools] with one or more version specs
+type Tool struct {
+	Name  string
+	Specs ToolSpecs
+}
+
+// ToolSpec represents a tool version and its options
+// (e.g., node = { version = "20", postinstall = "corepack enable" })
+type ToolSpec struct {
+	Version string
+	Options map[string]interface{}
+}
+
+// ToolSpecs represents the value of a tool in [tools]
+type ToolSpecs []ToolSpec
+
+// UnmarshalTOML accepts a version string, a table with version and options,
+// or an array of either
+func (s *ToolSpecs) UnmarshalTOML(data interface{}) error {
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+	for _, item := range items {
+		spec, err := parseToolSpec(item)
+		if err != nil {
+			return err
+		}
+		*s = append(*s, spec)
+	}
+	return nil
+}
+
+func parseToolSpec(data interface{}) (ToolSpec, error) {
+	switch v := data.(type) {
+	case string:
+		return ToolSpec{Version: v}, nil
+	case int64:
+		return ToolSpec{Version: strconv.FormatInt(v, 10)}, nil
+	case map[string]interface{}:
+		var spec ToolSpec
+		for key, value := range v {
+			if key == "version" {
+				version, err := parseToolSpec(value)
+				if err != nil || version.Options != nil {
+					return ToolSpec{}, fmt.Errorf("tool version must be a string, got %T", value)
+				}
+				spec.Version = version.Version
+				continue
+			}
+			if spec.Options == nil {
+				spec.Options = make(map[string]interface{})
+			}
+			spec.Options[key] = value
+		}
+		return spec, nil
+	}
+	return ToolSpec{}, fmt.Errorf("tool must be a version string or a table, got %T", data)
+}
+
+// ParseConfigFile parses the content of a mise configuration file
+func ParseConfigFile(content string) (*ConfigFile, error) {
+	var typed struct {
+		Tools    map[string]ToolSpecs   `toml:"tools"`
+		Env      map[string]interface{} `toml:"env"`
+		Tasks    map[string]interface{} `toml:"tasks"`
+		Settings map[string]interface{} `toml:"settings"`
+		Plugins  map[string]interface{} `toml:"plugins"`
+	}
+	md, err := toml.Decode(content, &typed)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]interface{}
+	if _, err := toml.Decode(content, &all); err != nil {
+		return nil, err
+	}
+
+	f := &ConfigFile{
+		Env:      typed.Env,
+		Tasks:    typed.Tasks,
+		Settings: typed.Settings,
+		Plugins:  typed.Plugins,
+	}
+
+	// Map iteration order is random; the metadata keeps the order of the file
+	for _, key := range md.Keys() {
+		if len(key) == 2 && key[0] == "tools" {
+			f.Tools = append(f.Tools, Tool{Name: key[1], Specs: typed.Tools[key[1]]})
+		}
+	}
+
+	for key, value := range all {
+		switch key {
+		case "tools", "env", "tasks", "settings", "plugins":
+			continue
+		}
+		if f.Other == nil {
+			f.Other = make(map[string]interface{})
+		}
+		f.Other[key] = value
+	}
+	return f, nil
+}
+
+// readConfigFile parses the mise configuration file at path,
+// returning an empty file if it does not exist
+func readConfigFile(path string) (*ConfigFile, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &ConfigFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfigFile(string(content))
+}
+
+// Encode returns the file as TOML: top-level keys, [tools], the known
+// sections and then the other tables
+func (f *ConfigFile) Encode() (string, error) {
+	values, tables := splitTables(f.Other)
+
+	var sb strings.Builder
+	if len(values) > 0 {
+		if err := encodeTable(&sb, values); err != nil {
+			return "", err
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("[tools]\n")
+	for _, tool := range f.Tools {
+		value, err := tool.Specs.inline()
+		if err != nil {
+			return "", fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		sb.WriteString(fmt.Sprintf("%s = %s\n", tomlKey(tool.Name), value))
+	}
+
+	for _, section := range []struct {
+		name  string
+		table map[string]interface{}
+	}{
+		{"env", f.Env},
+		{"tasks", f.Tasks},
+		{"settings", f.Settings},
+		{"plugins", f.Plugins},
+	} {
+		if section.table == nil {
+			continue
+		}
+		sb.WriteString("\n")
+		if err := encodeTable(&sb, map[string]interface{}{section.name: section.table}); err != nil {
+			return "", fmt.Errorf("[%s]: %w", section.name, err)
+		}
+	}
+
+	if len(tables) > 0 {
+		sb.WriteString("\n")
+		if err := encodeTable(&sb, tables); err != nil {
+			return "", err
+		}
+	}
+	return sb.String(), nil
+}
+
+// splitTables splits top-level entries into plain values and tables, since
+// plain values must come before the first table header
+func splitTables(entries map[string]interface{}) (values, tables map[string]interface{}) {
+	values = make(map[string]interface{})
+	tables = make(map[string]interface{})
+	for key, value := range entries {
+		switch value.(type) {
+		case map[string]interface{}, []map[string]interface{}:
+			tables[key] = value
+		default:
+			values[key] = value
+		}
+	}
+	return values, tables
+}
+
+func encodeTable(sb *strings.Builder, table map[string]interface{}) error {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(table); err != nil {
+		return err
+	}
+	sb.Write(buf.Bytes())
+	return nil
+}
+
+// inline returns the specs as a TOML value: a version string, an inline
+// table when there are options, or an array for several versions
+func (s ToolSpecs) inline() (string, error) {
+	var items []string
+	for _, spec := range s {
+		if len(spec.Options) == 0 {
+			items = append(items, tomlString(spec.Version))
+			continue
+		}
+		var fields []string
+		if spec.Version != "" {
+			fields = append(fields, "version = "+tomlString(spec.Version))
+		}
+		keys := make([]string, 0, len(spec.Options))
+		for key := range spec.Options {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, err := inlineValue(spec.Options[key])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, tomlKey(key)+" = "+value)
+		}
+		items = append(items, "{ "+strings.Join(fields, ", ")+" }")
+	}
+	if len(items) == 1 {
+		return items[0], nil
+	}
+	return "[" + strings.Join(items, ", ") + "]", nil
+}
+
+// inlineValue returns a decoded TOML value in inline form
+func inlineValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, key := range keys {
+			item, err := inlineValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, tomlKey(key)+" = "+item)
+		}
+		return "{ " + strings.Join(fields, ", ") + " }", nil
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = item
+		}
+		return inlineValue(items)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := inlineValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+
+	// Let the encoder format strings, numbers, booleans and datetimes
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"v": value}); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(buf.String(), "v = "), "\n"), nil
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey quotes a key unless it is a bare key (e.g., "npm:prettier")
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString returns s as a TOML basic string
+func tomlString(s string) string {
+	value, _ := inlineValue(s) // strings always encode
+	return value
+}
@@ -0,0 +1,183 @@
+package mise
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		tools    []Tool
+		sections []string // sections expected to be kept
+	}{
+		{
+			name: "node project with corepack",
+			input: `[tools]
+node = { version = "20", postinstall = "corepack enable" }
+"npm:prettier" = "3"
+
+[env]
+NODE_ENV = "development"
+_.path = ["./node_modules/.bin"]
+
+[tasks.lint]
+run = "prettier --check ."
+description = "Check formatting"
+`,
+			tools: []Tool{
+				{Name: "node", Specs: ToolSpecs{{Version: "20", Options: map[string]interface{}{"postinstall": "corepack enable"}}}},
+				{Name: "npm:prettier", Specs: ToolSpecs{{Version: "3"}}},
+			},
+			sections: []string{"env", "tasks"},
+		},
+		{
+			name: "python with virtualenv",
+			input: `min_version = "2024.9.0"
+
+[settings]
+experimental = true
+python.uv_venv_auto = true
+
+[tools]
+python = [
+  { version = "3.12", virtualenv = ".venv" },
+  "3.11",
+]
+uv = "latest"
+
+[env]
+_.python.venv = { path = ".venv", create = true }
+`,
+			tools: []Tool{
+				{Name: "python", Specs: ToolSpecs{{Version: "3.12", Options: map[string]interface{}{"virtualenv": ".venv"}}, {Version: "3.11"}}},
+				{Name: "uv", Specs: ToolSpecs{{Version: "latest"}}},
+			},
+			sections: []string{"settings", "env", "min_version"},
+		},
+		{
+			name: "backends and plugins",
+			input: `[tools]
+"aqua:cli/cli" = "2"
+"ubi:jqlang/jq" = { version = "1.7", exe = "jq" }
+terraform = "1.9"
+
+[plugins]
+terraform = "https://github.com/asdf-community/asdf-hashicorp.git"
+
+[alias.node]
+lts = "20"
+`,
+			tools: []Tool{
+				{Name: "aqua:cli/cli", Specs: ToolSpecs{{Version: "2"}}},
+				{Name: "ubi:jqlang/jq", Specs: ToolSpecs{{Version: "1.7", Options: map[string]interface{}{"exe": "jq"}}}},
+				{Name: "terraform", Specs: ToolSpecs{{Version: "1.9"}}},
+			},
+			sections: []string{"plugins", "alias"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseConfigFile(tt.input)
+			if err != nil {
+				t.Fatalf("ParseConfigFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(f.Tools, tt.tools) {
+				t.Errorf("Tools = %+v, want %+v", f.Tools, tt.tools)
+			}
+
+			// Encoding and parsing again must not lose anything
+			encoded, err := f.Encode()
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			again, err := ParseConfigFile(encoded)
+			if err != nil {
+				t.Fatalf("ParseConfigFile(Encode()) error = %v\n%s", err, encoded)
+			}
+			if !reflect.DeepEqual(again, f) {
+				t.Errorf("round trip = %+v, want %+v\n%s", again, f, encoded)
+			}
+
+			for _, section := range tt.sections {
+				if !strings.Contains(encoded, section) {
+					t.Errorf("Encode() lost %s:\n%s", section, encoded)
+				}
+			}
+		})
+	}
+}
+
+func TestTomlKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"node", "node"},
+		{"github-cli", "github-cli"},
+		{"npm:prettier", `"npm:prettier"`},
+		{"aqua:cli/cli", `"aqua:cli/cli"`},
+	}
+
+	for _, tt := range tests {
+		if result := tomlKey(tt.input); result != tt.expected {
+			t.Errorf("tomlKey(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestExportTOMLKeepsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	existing := `[tools]
+node = { version = "20.10.0", postinstall = "corepack enable" }
+ruby = "3.2"
+
+[env]
+FOO = "bar"
+
+[settings]
+experimental = true
+`
+	if err := os.WriteFile(filepath.Join(dir, ".mise.toml"), []byte(existing), 0644); err != nil {
+		t.Fatalf("failed to write .mise.toml: %v", err)
+	}
+
+	content, err := exportTOML(dir, []InstalledTool{
+		{Name: "node", Version: "20.10.0"},
+		{Name: "python", Version: "3.12.0"},
+		{Name: "python", Version: "3.11.0"},
+	})
+	if err != nil {
+		t.Fatalf("exportTOML() error = %v", err)
+	}
+
+	expected := `# Generated by goodbye export mise
+[tools]
+node = { version = "20.10.0", postinstall = "corepack enable" }
+python = ["3.12.0", "3.11.0"]
+
+[env]
+FOO = "bar"
+
+[settings]
+experimental = true
+`
+	if content != expected {
+		t.Errorf("exportTOML() = %q, want %q", content, expected)
+	}
+}
+
+func TestExportTOMLInvalidExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".mise.toml"), []byte("[tools"), 0644); err != nil {
+		t.Fatalf("failed to write .mise.toml: %v", err)
+	}
+	if _, err := exportTOML(dir, []InstalledTool{{Name: "node", Version: "20"}}); err == nil {
+		t.Error("exportTOML() should return error for an invalid .mise.toml")
+	}
+}
@@ -213,7 +213,7 @@ func resolveHomebrewPrefix() string {
 		return prefix
 	}
 
-	candidates := []string{"/opt/homebrew", "/usr/local"}
+	candidates := []string{"/opt/homebrew", "/home/linuxbrew/.linuxbrew", "/usr/local"}
 	for _, candidate := range candidates {
 		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
 			return candidate
@@ -0,0 +1,397 @@
+package uv
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yyYank/goodbye/internal/brew"
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+// MigrateOptions represents options for the brew --uv command
+type MigrateOptions struct {
+	DryRun  bool
+	Verbose bool
+}
+
+// MigrationCandidate represents a Python CLI tool that can be migrated
+type MigrationCandidate struct {
+	BrewName    string
+	PackageName string
+	Python      string // python@3.x dependency of the formula
+}
+
+// brewInfo represents the subset of "brew info --json=v2" used here
+type brewInfo struct {
+	Formulae []brewFormula `json:"formulae"`
+}
+
+type brewFormula struct {
+	Name         string   `json:"name"`
+	Dependencies []string `json:"dependencies"`
+}
+
+var pythonDependencyPattern = regexp.MustCompile(`^python@3(\.\d+)?$`)
+
+// Migrate performs the brew to uv migration
+func Migrate(cfg *config.Config, opts MigrateOptions) error {
+	// Step 1: Get Homebrew formula list
+	fmt.Println("Getting Homebrew formula list...")
+	formulas, err := getBrewFormulas(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get brew formulas: %w", err)
+	}
+	fmt.Printf("Found %d formulas\n", len(formulas))
+
+	// Step 2: Get dependency data
+	fmt.Println("Getting Homebrew dependency data...")
+	info, err := getBrewInfo(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get brew info: %w", err)
+	}
+
+	// Step 3: Find migration candidates
+	candidates, unmapped := findCandidates(formulas, info, cfg.Uv.PackageMappings)
+	if len(unmapped) > 0 {
+		fmt.Printf("\n%d formulas depend on python@3 but need mapping (add them to [uv.package_mappings] to migrate):\n", len(unmapped))
+		for _, formula := range unmapped {
+			fmt.Printf("  - %s\n", formula)
+		}
+	}
+	if len(candidates) == 0 {
+		fmt.Println("\nNo migration candidates found.")
+		return nil
+	}
+
+	fmt.Printf("\nFound %d migration candidates:\n", len(candidates))
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Printf("%-25s %-20s %s\n", "BREW", "PYPI", "PYTHON")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, c := range candidates {
+		fmt.Printf("%-25s %-20s %s\n", c.BrewName, c.PackageName, c.Python)
+	}
+	fmt.Println(strings.Repeat("-", 60))
+
+	if opts.DryRun {
+		fmt.Println("\n[dry-run] Would perform the following actions:")
+		for _, c := range candidates {
+			fmt.Printf("  1. uv tool install %s\n", c.PackageName)
+			fmt.Printf("  2. Verify entry points resolve to the uv tool bin directory\n")
+			fmt.Printf("  3. brew uninstall %s\n", c.BrewName)
+			fmt.Println()
+		}
+		fmt.Println("\nTo apply these changes, run with --apply")
+		return nil
+	}
+
+	// Step 4: Confirm
+	fmt.Print("\nDo you want to proceed with migration? [y/N]: ")
+	var response string
+	fmt.Scanln(&response)
+	if strings.ToLower(response) != "y" {
+		fmt.Println("Migration cancelled.")
+		return nil
+	}
+
+	// Step 5-7: Migrate each candidate
+	var succeeded, failed []MigrationCandidate
+	for _, c := range candidates {
+		fmt.Printf("\nMigrating %s -> uv tool %s\n", c.BrewName, c.PackageName)
+
+		// Install with uv
+		fmt.Printf("  Installing %s with uv...\n", c.PackageName)
+		installCmd := cfg.Uv.Commands.InstallCmd
+		if installCmd == "" {
+			installCmd = "uv tool install %s"
+		}
+		if err := runCommand(fmt.Sprintf(installCmd, c.PackageName), opts.Verbose); err != nil {
+			fmt.Printf("  Failed to install: %v\n", err)
+			failed = append(failed, c)
+			continue
+		}
+
+		// Verify entry points
+		fmt.Printf("  Verifying entry points...\n")
+		if err := verifyInstallation(cfg, c); err != nil {
+			fmt.Printf("  Verification failed: %v\n", err)
+			failed = append(failed, c)
+			continue
+		}
+
+		// Uninstall from brew
+		fmt.Printf("  Uninstalling %s from brew...\n", c.BrewName)
+		brewUninstallCmd := cfg.Uv.Commands.BrewUninstallCmd
+		if brewUninstallCmd == "" {
+			brewUninstallCmd = "brew uninstall %s"
+		}
+		if err := runCommand(fmt.Sprintf(brewUninstallCmd, c.BrewName), opts.Verbose); err != nil {
+			fmt.Printf("  Warning: Failed to uninstall from brew: %v\n", err)
+			// Still consider it a success since uv is working
+		}
+
+		fmt.Printf("  Successfully migrated %s!\n", c.BrewName)
+		succeeded = append(succeeded, c)
+	}
+
+	// Summary
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("Migration Summary")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("Succeeded: %d\n", len(succeeded))
+	for _, c := range succeeded {
+		fmt.Printf("  - %s -> %s\n", c.BrewName, c.PackageName)
+	}
+	if len(failed) > 0 {
+		fmt.Printf("Failed: %d\n", len(failed))
+		for _, c := range failed {
+			fmt.Printf("  - %s\n", c.BrewName)
+		}
+	}
+
+	return nil
+}
+
+func getBrewFormulas(cfg *config.Config) ([]string, error) {
+	// Use command from config, fallback to default
+	cmdStr := cfg.Brew.Export.FormulaCmd
+	if cmdStr == "" {
+		cmdStr = "brew list --installed-on-request"
+	}
+	return runCommandLines(cmdStr)
+}
+
+func getBrewInfo(cfg *config.Config) (*brewInfo, error) {
+	// Use command from config, fallback to default
+	cmdStr := cfg.Uv.Commands.BrewInfoCmd
+	if cmdStr == "" {
+		cmdStr = "brew info --json=v2 --installed"
+	}
+
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseBrewInfo(output)
+}
+
+func parseBrewInfo(data []byte) (*brewInfo, error) {
+	var info brewInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// pythonDependency returns the python@3.x dependency of a formula, if any
+func pythonDependency(f brewFormula) string {
+	for _, dep := range f.Dependencies {
+		if pythonDependencyPattern.MatchString(dep) {
+			return dep
+		}
+	}
+	return ""
+}
+
+// isPythonLibrary reports whether a formula is Python itself or a Python
+// library formula (python-setuptools, python-tk@3.12, ...) rather than an application
+func isPythonLibrary(name string) bool {
+	return strings.HasPrefix(name, "python@") || strings.HasPrefix(name, "python-")
+}
+
+// findCandidates returns the formulas that depend on python@3 and are
+// listed in the package mappings. Python applications without a mapping are
+// returned as unmapped: the PyPI package is never guessed from the formula
+// name, since it is often a different project (e.g., brew awscli is v2,
+// PyPI awscli is v1) or not a Python package at all (e.g., vim, llvm).
+func findCandidates(formulas []string, info *brewInfo, mappings map[string]string) (candidates []MigrationCandidate, unmapped []string) {
+	byName := make(map[string]brewFormula)
+	for _, f := range info.Formulae {
+		byName[f.Name] = f
+	}
+
+	for _, formula := range formulas {
+		f, ok := byName[formula]
+		if !ok {
+			continue
+		}
+
+		python := pythonDependency(f)
+		if python == "" {
+			continue
+		}
+
+		// Mappings take precedence, so python-* applications can be listed explicitly
+		packageName, mapped := mappings[formula]
+		if !mapped {
+			if !isPythonLibrary(formula) {
+				unmapped = append(unmapped, formula)
+			}
+			continue
+		}
+		if packageName == "" {
+			continue
+		}
+
+		candidates = append(candidates, MigrationCandidate{
+			BrewName:    formula,
+			PackageName: packageName,
+			Python:      python,
+		})
+	}
+
+	return candidates, unmapped
+}
+
+// parseToolList parses "uv tool list" output into package -> entry points
+//
+//	httpie v3.2.2
+//	- http
+//	- https
+func parseToolList(output string) map[string][]string {
+	tools := make(map[string][]string)
+	current := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "- ") {
+			if current != "" {
+				tools[current] = append(tools[current], strings.TrimSpace(line[2:]))
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		current = strings.ToLower(fields[0])
+		if _, ok := tools[current]; !ok {
+			tools[current] = nil
+		}
+	}
+
+	for name := range tools {
+		sort.Strings(tools[name])
+	}
+	return tools
+}
+
+// packageBaseName strips extras and version specifiers from a package spec
+// (e.g., "httpie[socks]==3.2.2" -> "httpie")
+func packageBaseName(spec string) string {
+	if idx := strings.IndexAny(spec, "[=<>~!@ "); idx >= 0 {
+		spec = spec[:idx]
+	}
+	return strings.ToLower(spec)
+}
+
+// verifyInstallation checks that uv lists the package with entry points and
+// that each entry point resolves on PATH to the uv tool bin directory. The
+// brew copy is still installed at this point, so an entry point resolving
+// anywhere else (e.g., to Homebrew) does not prove that the uv install works.
+func verifyInstallation(cfg *config.Config, c MigrationCandidate) error {
+	// Use command from config, fallback to default
+	cmdStr := cfg.Uv.Commands.ListCmd
+	if cmdStr == "" {
+		cmdStr = "uv tool list"
+	}
+
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+
+	entryPoints, ok := parseToolList(string(output))[packageBaseName(c.PackageName)]
+	if !ok {
+		return fmt.Errorf("%s is not listed by uv tool list", c.PackageName)
+	}
+	if len(entryPoints) == 0 {
+		return fmt.Errorf("%s has no entry points", c.PackageName)
+	}
+
+	binDir, err := toolBinDir(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get uv tool bin directory: %w", err)
+	}
+
+	for _, entry := range entryPoints {
+		if err := checkEntryPoint(entry, binDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toolBinDir returns the directory uv installs tool entry points to
+func toolBinDir(cfg *config.Config) (string, error) {
+	// Use command from config, fallback to default
+	cmdStr := cfg.Uv.Commands.ToolDirCmd
+	if cmdStr == "" {
+		cmdStr = "uv tool dir --bin"
+	}
+
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(output))
+	if dir == "" {
+		return "", fmt.Errorf("%s printed nothing", cmdStr)
+	}
+	return filepath.Clean(dir), nil
+}
+
+// checkEntryPoint resolves an entry point on PATH and verifies that it is
+// the uv copy in binDir rather than a Homebrew copy shadowing it
+func checkEntryPoint(entry, binDir string) error {
+	path, err := exec.LookPath(entry)
+	if err != nil {
+		return fmt.Errorf("entry point %s does not resolve on PATH: %w", entry, err)
+	}
+	if filepath.Dir(path) == binDir {
+		return nil
+	}
+	if brew.IsHomebrewPath(path) {
+		return fmt.Errorf("entry point %s resolves to the Homebrew copy %s (check that %s comes first on PATH)", entry, path, binDir)
+	}
+	return fmt.Errorf("entry point %s resolves to %s, which is not in the uv tool bin directory %s", entry, path, binDir)
+}
+
+func runCommand(cmdStr string, verbose bool) error {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	if verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	return cmd.Run()
+}
+
+func runCommandLines(cmdStr string) ([]string, error) {
+	cmd := exec.Command("sh", "-c", cmdStr)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,210 @@
+package uv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yyYank/goodbye/internal/config"
+)
+
+const sampleBrewInfo = `{
+  "formulae": [
+    {"name": "httpie", "dependencies": ["python@3.12"]},
+    {"name": "poetry", "dependencies": ["cffi", "python@3.13"]},
+    {"name": "python-yq", "dependencies": ["jq", "python@3.12"]},
+    {"name": "python-setuptools", "dependencies": ["python@3.12"]},
+    {"name": "python@3.12", "dependencies": ["openssl@3", "sqlite"]},
+    {"name": "jq", "dependencies": ["oniguruma"]},
+    {"name": "awscli", "dependencies": ["python@3.12"]}
+  ],
+  "casks": []
+}`
+
+func TestParseBrewInfo(t *testing.T) {
+	info, err := parseBrewInfo([]byte(sampleBrewInfo))
+	if err != nil {
+		t.Fatalf("parseBrewInfo() error = %v", err)
+	}
+	if len(info.Formulae) != 7 {
+		t.Fatalf("len(Formulae) = %d, want 7", len(info.Formulae))
+	}
+	if info.Formulae[1].Name != "poetry" {
+		t.Errorf("Formulae[1].Name = %q, want %q", info.Formulae[1].Name, "poetry")
+	}
+}
+
+func TestParseBrewInfoInvalid(t *testing.T) {
+	if _, err := parseBrewInfo([]byte("not json")); err == nil {
+		t.Error("parseBrewInfo() should return error for invalid JSON")
+	}
+}
+
+func TestFindCandidates(t *testing.T) {
+	info, err := parseBrewInfo([]byte(sampleBrewInfo))
+	if err != nil {
+		t.Fatalf("parseBrewInfo() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		formulas []string
+		mappings map[string]string
+		expected []MigrationCandidate
+		unmapped []string
+	}{
+		{
+			name:     "no python applications",
+			formulas: []string{"jq", "python@3.12"},
+			expected: nil,
+		},
+		{
+			name:     "unmapped applications need mapping",
+			formulas: []string{"httpie", "awscli"},
+			expected: nil,
+			unmapped: []string{"httpie", "awscli"},
+		},
+		{
+			name:     "mapped applications",
+			formulas: []string{"httpie", "poetry"},
+			mappings: map[string]string{"httpie": "httpie", "poetry": "poetry"},
+			expected: []MigrationCandidate{
+				{BrewName: "httpie", PackageName: "httpie", Python: "python@3.12"},
+				{BrewName: "poetry", PackageName: "poetry", Python: "python@3.13"},
+			},
+		},
+		{
+			name:     "python libraries are skipped",
+			formulas: []string{"python-setuptools", "python-yq"},
+			expected: nil,
+		},
+		{
+			name:     "mapping renames package",
+			formulas: []string{"python-yq"},
+			mappings: map[string]string{"python-yq": "yq"},
+			expected: []MigrationCandidate{
+				{BrewName: "python-yq", PackageName: "yq", Python: "python@3.12"},
+			},
+		},
+		{
+			name:     "empty mapping excludes formula",
+			formulas: []string{"awscli", "httpie"},
+			mappings: map[string]string{"awscli": "", "httpie": "httpie"},
+			expected: []MigrationCandidate{
+				{BrewName: "httpie", PackageName: "httpie", Python: "python@3.12"},
+			},
+		},
+		{
+			name:     "formula missing from brew info",
+			formulas: []string{"black"},
+			mappings: map[string]string{"black": "black"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, unmapped := findCandidates(tt.formulas, info, tt.mappings)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("findCandidates() = %v, want %v", result, tt.expected)
+			}
+			if !reflect.DeepEqual(unmapped, tt.unmapped) {
+				t.Errorf("findCandidates() unmapped = %v, want %v", unmapped, tt.unmapped)
+			}
+		})
+	}
+}
+
+func TestParseToolList(t *testing.T) {
+	output := `black v24.1.0
+- black
+- blackd
+httpie v3.2.2
+- https
+- http
+- httpie
+`
+	expected := map[string][]string{
+		"black":  {"black", "blackd"},
+		"httpie": {"http", "httpie", "https"},
+	}
+
+	result := parseToolList(output)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parseToolList() = %v, want %v", result, expected)
+	}
+}
+
+func TestPackageBaseName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"httpie", "httpie"},
+		{"HTTPie", "httpie"},
+		{"httpie[socks]", "httpie"},
+		{"black==24.1.0", "black"},
+		{"ansible>=9", "ansible"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := packageBaseName(tt.input); result != tt.expected {
+				t.Errorf("packageBaseName(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// writeEntryPoint creates an executable entry point at dir/name
+func writeEntryPoint(t *testing.T, dir, name string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestVerifyInstallationWithCustomCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	uvBin := filepath.Join(tmpDir, "uv", "bin")
+	brewBin := filepath.Join(tmpDir, "brew", "bin")
+	writeEntryPoint(t, uvBin, "fake-uv")
+	writeEntryPoint(t, brewBin, "fake-brew")
+	t.Setenv("PATH", strings.Join([]string{brewBin, uvBin, os.Getenv("PATH")}, string(os.PathListSeparator)))
+	t.Setenv("HOMEBREW_PREFIX", filepath.Join(tmpDir, "brew"))
+
+	cfg := config.DefaultConfig()
+	cfg.Uv.Commands.ToolDirCmd = "echo " + uvBin
+
+	tests := []struct {
+		name    string
+		entry   string
+		wantErr string
+	}{
+		{"entry point in uv bin dir", "fake-uv", ""},
+		{"entry point shadowed by Homebrew", "fake-brew", "Homebrew copy"},
+		{"entry point outside uv bin dir", "sh", "not in the uv tool bin directory"},
+		{"entry point not on PATH", "goodbye-nonexistent-binary", "does not resolve on PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Uv.Commands.ListCmd = "printf 'fake v1.0.0\\n- " + tt.entry + "\\n'"
+			err := verifyInstallation(cfg, MigrationCandidate{BrewName: "fake", PackageName: "fake"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("verifyInstallation() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verifyInstallation() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}